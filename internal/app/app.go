@@ -3,11 +3,13 @@ package app
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/jedwards1230/deck/internal/code"
 	"github.com/jedwards1230/deck/internal/diff"
@@ -18,6 +20,8 @@ import (
 	"github.com/jedwards1230/deck/internal/search"
 )
 
+var problemStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+
 // Model is the bubbletea model for the slide presenter.
 type Model struct {
 	presentation *model.Presentation
@@ -27,7 +31,11 @@ type Model struct {
 	height       int
 	ready        bool
 	filePath     string // empty if reading from stdin
+	content      string // the deck's markdown, reparsed to switch language
+	opts         Options
 	codeOutput   string // virtual text from code execution
+	showNotes    bool   // show speaker notes below the slide
+	hideProblems bool   // frontmatter problems were dismissed with esc
 
 	// search state
 	searching   bool
@@ -37,27 +45,66 @@ type Model struct {
 	// code execution confirmation state
 	confirming   bool
 	pendingBlock code.Block
+
+	// link following state
+	choosingLink bool
+	jumps        []nav.State // where each jump came from, most recent last
 }
 
-// New creates a new Model from the given content.
+// Options configure how a Model parses and presents a deck.
+type Options struct {
+	Parse      parse.Options // used for the first parse and every reload
+	ShowHidden bool          // present hidden slides like any other
+}
+
+// New creates a new Model from the given content, read from filePath.
 func New(content string, filePath string) Model {
+	return NewWithOptions(content, Options{Parse: parse.Options{Path: filePath}})
+}
+
+// NewWithOptions creates a new Model from the given content, configured by
+// opts.
+func NewWithOptions(content string, opts Options) Model {
 	isDark := lipgloss.HasDarkBackground(os.Stdin, os.Stdout)
-	pres := parse.ParsePresentation(content)
+	pres := parse.Parse(content, opts.Parse)
 
-	totalSlides := len(pres.Slides)
-	chunksInSlide := 1
-	if totalSlides > 0 && len(pres.Slides[0].Chunks) > 0 {
-		chunksInSlide = len(pres.Slides[0].Chunks)
+	m := Model{
+		presentation: pres,
+		state:        nav.State{TotalSlides: len(pres.Slides)},
+		cache:        render.NewRendererCache(isDark),
+		filePath:     opts.Parse.Path,
+		content:      content,
+		opts:         opts,
 	}
+	m.syncNav()
 
-	return Model{
-		presentation: pres,
-		state: nav.State{
-			TotalSlides:   totalSlides,
-			ChunksInSlide: chunksInSlide,
-		},
-		cache:    render.NewRendererCache(isDark),
-		filePath: filePath,
+	// Start on the first slide that is not hidden
+	m.state.SlideIndex = m.state.FirstVisible()
+	m.state.ChunksInSlide = 1
+	if m.state.SlideIndex < len(pres.Slides) && len(pres.Slides[m.state.SlideIndex].Chunks) > 0 {
+		m.state.ChunksInSlide = len(pres.Slides[m.state.SlideIndex].Chunks)
+	}
+	return m
+}
+
+// syncNav marks the presentation's section starts and, unless hidden
+// slides are shown, its hidden slides in the navigation state.
+func (m *Model) syncNav() {
+	m.state.Hidden, m.state.Sections = nil, nil
+	hidden := make([]bool, len(m.presentation.Slides))
+	sections := make([]bool, len(m.presentation.Slides))
+	anyHidden, anySections := false, false
+	for i, slide := range m.presentation.Slides {
+		hidden[i] = slide.Meta.Hidden && !m.opts.ShowHidden
+		sections[i] = slide.Meta.Section != ""
+		anyHidden = anyHidden || hidden[i]
+		anySections = anySections || sections[i]
+	}
+	if anyHidden {
+		m.state.Hidden = &hidden
+	}
+	if anySections {
+		m.state.Sections = &sections
 	}
 }
 
@@ -105,6 +152,10 @@ func (m Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.handleSearchInput(msg)
 	}
 
+	if m.choosingLink {
+		return m.handleLinkInput(msg)
+	}
+
 	// Guard against empty presentations
 	if len(m.presentation.Slides) == 0 {
 		if key == "q" || key == "ctrl+c" {
@@ -120,6 +171,7 @@ func (m Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 
 	case "esc":
 		m.codeOutput = ""
+		m.hideProblems = true
 		return m, nil
 
 	case "/":
@@ -134,6 +186,23 @@ func (m Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.yankCode()
 		return m, nil
 
+	case "f":
+		// List the slide's links to pick one to follow
+		m.choosingLink = len(m.presentation.Slides[m.state.SlideIndex].Links) > 0
+		return m, nil
+
+	case "b":
+		m.jumpBack()
+		return m, nil
+
+	case "s":
+		m.showNotes = !m.showNotes
+		return m, nil
+
+	case "L":
+		m.switchLanguage()
+		return m, nil
+
 	case "ctrl+n":
 		// Search next
 		if m.lastSearch != "" {
@@ -176,15 +245,56 @@ func (m Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// jumpToSlide shows slide idx from its first step, remembering the current
+// position for jumpBack.
 func (m *Model) jumpToSlide(idx int) {
 	if idx < 0 || idx >= len(m.presentation.Slides) {
 		return
 	}
+	if idx != m.state.SlideIndex {
+		m.jumps = append(m.jumps, m.state)
+	}
 	m.state.SlideIndex = idx
 	m.state.ChunkIndex = 0
 	m.state.ChunksInSlide = len(m.presentation.Slides[idx].Chunks)
 }
 
+// jumpBack returns to where the most recent jump came from.
+func (m *Model) jumpBack() {
+	if len(m.jumps) == 0 {
+		return
+	}
+	prev := m.jumps[len(m.jumps)-1]
+	m.jumps = m.jumps[:len(m.jumps)-1]
+	if prev.SlideIndex >= len(m.presentation.Slides) {
+		return // the slide was removed by a reload
+	}
+
+	m.codeOutput = ""
+	m.state.SlideIndex = prev.SlideIndex
+	m.state.ChunksInSlide = len(m.presentation.Slides[prev.SlideIndex].Chunks)
+	m.state.ChunkIndex = min(prev.ChunkIndex, m.state.ChunksInSlide-1)
+}
+
+// handleLinkInput follows the link picked by number from the footer list.
+func (m Model) handleLinkInput(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	m.choosingLink = false
+	links := m.presentation.Slides[m.state.SlideIndex].Links
+	key := msg.String()
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return m, nil
+	}
+	n := int(key[0] - '1')
+	if n >= len(links) {
+		return m, nil
+	}
+	if idx := model.AnchorIndex(m.presentation.Slides, links[n].Target); idx >= 0 {
+		m.codeOutput = ""
+		m.jumpToSlide(idx)
+	}
+	return m, nil
+}
+
 func (m Model) confirmExec() (tea.Model, tea.Cmd) {
 	if m.state.SlideIndex >= len(m.presentation.Slides) {
 		return m, nil
@@ -241,7 +351,12 @@ func (m Model) handleSearchInput(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.searching = false
 		if m.searchQuery != "" {
 			m.lastSearch = m.searchQuery
-			result := search.Search(m.presentation.Slides, m.searchQuery, m.state.SlideIndex)
+			// #id jumps to the slide with that id before falling back to
+			// a content search.
+			result := search.FindID(m.presentation.Slides, strings.TrimPrefix(m.searchQuery, "#"))
+			if !strings.HasPrefix(m.searchQuery, "#") || !result.Found {
+				result = search.Search(m.presentation.Slides, m.searchQuery, m.state.SlideIndex)
+			}
 			if result.Found {
 				m.jumpToSlide(result.SlideIndex)
 			}
@@ -268,19 +383,46 @@ func (m Model) handleSearchInput(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleFileChanged(msg FileChangedMsg) (tea.Model, tea.Cmd) {
-	newPres := parse.ParsePresentation(msg.Content)
+	newPres := parse.Parse(msg.Content, m.opts.Parse)
 
 	jumpTo := diff.FindModified(m.presentation, newPres)
 
+	m.content = msg.Content
 	m.presentation = newPres
+	m.hideProblems = false
 	m.state.TotalSlides = len(newPres.Slides)
+	m.syncNav()
 
 	if jumpTo >= 0 && jumpTo < len(newPres.Slides) {
 		m.state.SlideIndex = jumpTo
 		m.state.ChunkIndex = 0
 	}
+	m.clampState()
+
+	return m, nil
+}
+
+// switchLanguage reparses the deck in the next of its languages, staying
+// on the same slide and step.
+func (m *Model) switchLanguage() {
+	langs := m.presentation.Languages
+	if len(langs) == 0 {
+		return
+	}
+	next := langs[0]
+	if i := slices.Index(langs, m.presentation.Frontmatter.Lang); i >= 0 {
+		next = langs[(i+1)%len(langs)]
+	}
+	m.opts.Parse.Lang = next
+	m.presentation = parse.Parse(m.content, m.opts.Parse)
+	m.state.TotalSlides = len(m.presentation.Slides)
+	m.syncNav()
+	m.clampState()
+}
 
-	// Clamp slide index
+// clampState keeps the slide and step within the presentation after it
+// is reparsed.
+func (m *Model) clampState() {
 	if m.state.SlideIndex >= m.state.TotalSlides {
 		m.state.SlideIndex = m.state.TotalSlides - 1
 	}
@@ -288,15 +430,12 @@ func (m Model) handleFileChanged(msg FileChangedMsg) (tea.Model, tea.Cmd) {
 		m.state.SlideIndex = 0
 	}
 
-	// Update chunks in slide
-	if m.state.SlideIndex < len(newPres.Slides) {
-		m.state.ChunksInSlide = len(newPres.Slides[m.state.SlideIndex].Chunks)
+	if m.state.SlideIndex < len(m.presentation.Slides) {
+		m.state.ChunksInSlide = len(m.presentation.Slides[m.state.SlideIndex].Chunks)
 		if m.state.ChunkIndex >= m.state.ChunksInSlide {
 			m.state.ChunkIndex = m.state.ChunksInSlide - 1
 		}
 	}
-
-	return m, nil
 }
 
 func (m Model) View() tea.View {
@@ -314,7 +453,23 @@ func (m Model) View() tea.View {
 
 	// Render slide content
 	slide := m.presentation.Slides[m.state.SlideIndex]
+	notes := m.notesPanel(slide)
+	footnotes := render.RenderFootnotes(slide.VisibleFootnotes(m.state.ChunkIndex), m.width)
+	below := lipgloss.Height(notes)
+	if footnotes != "" {
+		below += lipgloss.Height(footnotes)
+	}
 	rendered, _ := render.RenderSlide(slide, m.state.ChunkIndex, m.width, m.cache)
+	rendered = render.Place(rendered, m.width, contentHeight-below, slide.Align, slide.VAlign)
+	if footnotes != "" {
+		// Footnotes sit at the bottom of the content area, whatever the
+		// slide's alignment
+		gap := contentHeight - below - lipgloss.Height(rendered)
+		rendered += strings.Repeat("\n", max(0, gap)+1) + footnotes
+	}
+	if notes != "" {
+		rendered += "\n" + notes
+	}
 
 	// Append code output if present
 	if m.codeOutput != "" {
@@ -327,13 +482,20 @@ func (m Model) View() tea.View {
 		rendered += strings.Repeat("\n", contentHeight-renderedLines)
 	}
 
-	// Render footer
-	footer := render.RenderFooter(
-		m.presentation.Frontmatter,
-		m.state.SlideIndex,
-		m.state.TotalSlides,
-		m.width,
-	)
+	// Render footer, keeping its lines blank on slides that hide it
+	footer := "\n"
+	if slide.Meta.ShowFooter() {
+		page, pages := m.state.Page()
+		section := render.Section{Name: model.SectionName(m.presentation.Slides, m.state.SlideIndex)}
+		section.Slide, section.Total = m.state.SectionPage()
+		footer = render.RenderFooter(
+			m.presentation.Frontmatter,
+			page,
+			pages,
+			section,
+			m.width,
+		)
+	}
 
 	// Overlay: confirmation prompt takes priority over search bar
 	if m.confirming {
@@ -342,8 +504,68 @@ func (m Model) View() tea.View {
 	} else if m.searching {
 		searchBar := fmt.Sprintf("/%s█", m.searchQuery)
 		footer = searchBar + strings.Repeat(" ", max(0, m.width-lipgloss.Width(searchBar))) + "\n"
+	} else if m.choosingLink {
+		footer = m.linkBar() + "\n"
+	} else if bar := m.problemBar(); bar != "" {
+		footer = bar + "\n"
 	}
 
 	v.SetContent(rendered + footer)
 	return v
 }
+
+// FrontmatterProblems returns the diagnostics about the deck's frontmatter,
+// such as YAML errors and unknown keys, which leave settings unapplied.
+func (m Model) FrontmatterProblems() []model.Diagnostic {
+	var problems []model.Diagnostic
+	for _, d := range m.presentation.Diagnostics {
+		if strings.HasPrefix(d.Code, "frontmatter-") || d.Code == "unclosed-frontmatter" {
+			problems = append(problems, d)
+		}
+	}
+	return problems
+}
+
+// problemBar shows the first frontmatter problem in place of the footer
+// until it is dismissed, so a broken setting is not silently ignored.
+func (m Model) problemBar() string {
+	problems := m.FrontmatterProblems()
+	if m.hideProblems || len(problems) == 0 {
+		return ""
+	}
+	d := problems[0]
+	bar := fmt.Sprintf("line %d: %s", d.Span.StartLine, d.Message)
+	if len(problems) > 1 {
+		bar += fmt.Sprintf(" (+%d more)", len(problems)-1)
+	}
+	const dismiss = " · esc"
+	bar = ansi.Truncate(bar, max(0, m.width-lipgloss.Width(dismiss)), "…") + dismiss
+	return problemStyle.Render(bar + strings.Repeat(" ", max(0, m.width-lipgloss.Width(bar))))
+}
+
+// notesPanel renders the slide's speaker notes under a divider when they
+// are shown, or returns "".
+func (m Model) notesPanel(slide model.Slide) string {
+	if !m.showNotes || len(slide.SpeakerNotes) == 0 {
+		return ""
+	}
+	notes, _ := render.RenderNotes(slide.SpeakerNotes, m.width, m.cache)
+	return render.Divider(m.width) + "\n" + notes
+}
+
+// linkBar lists the current slide's links by number, to pick one to follow.
+func (m Model) linkBar() string {
+	parts := []string{"Follow link:"}
+	for i, link := range m.presentation.Slides[m.state.SlideIndex].Links {
+		if i == 9 {
+			break
+		}
+		text := link.Text
+		if text == "" {
+			text = "#" + link.Target
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", i+1, text))
+	}
+	bar := ansi.Truncate(strings.Join(parts, "  "), m.width, "…")
+	return bar + strings.Repeat(" ", max(0, m.width-lipgloss.Width(bar)))
+}
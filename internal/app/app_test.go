@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const testPresentation = `---
@@ -195,3 +196,278 @@ func TestModelEscClearsCodeOutput(t *testing.T) {
 		t.Errorf("esc should clear code output, got %q", m.codeOutput)
 	}
 }
+
+func TestModelSlideMeta(t *testing.T) {
+	content := "---\nauthor: Tester\n---\n" +
+		"<!-- slide: {id: cover, footer: false} -->\n# Cover\n---\n# Body\n---\n" +
+		"<!-- slide: {id: end} -->\n# End"
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	if v := m.View(); strings.Contains(v.Content, "Tester") {
+		t.Error("footer should be hidden on a slide with footer: false")
+	}
+
+	for _, key := range []rune{'/', '#', 'e', 'n', 'd'} {
+		newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: key}))
+		m = newModel.(Model)
+	}
+	newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: tea.KeyEnter}))
+	m = newModel.(Model)
+	if m.state.SlideIndex != 2 {
+		t.Errorf("#end should jump to slide 2, got %d", m.state.SlideIndex)
+	}
+	if v := m.View(); !strings.Contains(v.Content, "Tester") {
+		t.Error("footer should be shown on slides without footer: false")
+	}
+}
+
+func TestModelColumnReveal(t *testing.T) {
+	content := "<!-- column_layout: [1, 1] -->\n<!-- column: 0 -->\nLeftOne\n<!-- pause -->\n" +
+		"<!-- column: 1 -->\nRightOne\n<!-- reset_layout -->\n---\n# Next"
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	if v := m.View(); !strings.Contains(v.Content, "LeftOne") || strings.Contains(v.Content, "RightOne") {
+		t.Errorf("first step should show only the left column:\n%s", v.Content)
+	}
+
+	newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: 'l'}))
+	m = newModel.(Model)
+	if m.state.SlideIndex != 0 || m.state.ChunkIndex != 1 {
+		t.Fatalf("after 'l': slide=%d chunk=%d, want slide=0 chunk=1", m.state.SlideIndex, m.state.ChunkIndex)
+	}
+	if v := m.View(); !strings.Contains(v.Content, "RightOne") {
+		t.Errorf("second step should reveal the right column:\n%s", v.Content)
+	}
+}
+
+func TestModelFollowLink(t *testing.T) {
+	content := "# Intro\n\nJump to [the demo](#demo) or [the end](#the-end)\n---\n# Middle\n---\n" +
+		"<!-- slide: {id: demo} -->\n# Demo\n---\n# The End"
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	press := func(key rune) {
+		newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: key}))
+		m = newModel.(Model)
+	}
+
+	press('f')
+	if v := m.View(); !strings.Contains(v.Content, "1 the demo") || !strings.Contains(v.Content, "2 the end") {
+		t.Errorf("footer should list the links, got:\n%s", v.Content)
+	}
+	press('2')
+	if m.state.SlideIndex != 3 {
+		t.Fatalf("link 2 should jump to slide 3, got %d", m.state.SlideIndex)
+	}
+
+	press('b')
+	if m.state.SlideIndex != 0 {
+		t.Errorf("'b' should return to slide 0, got %d", m.state.SlideIndex)
+	}
+	press('b')
+	if m.state.SlideIndex != 0 {
+		t.Errorf("'b' with no jumps should stay, got %d", m.state.SlideIndex)
+	}
+
+	// A key other than a link number closes the list without moving.
+	press('f')
+	press('l')
+	if m.choosingLink || m.state.SlideIndex != 0 {
+		t.Errorf("'l' while choosing: choosing=%v slide=%d, want closed on slide 0", m.choosingLink, m.state.SlideIndex)
+	}
+}
+
+func TestModelHiddenSlides(t *testing.T) {
+	content := "---\npaging: \"%d of %d\"\n---\n# One\n---\n<!-- skip -->\n# Secret\n---\n# Two\n---\n" +
+		"<!-- slide: {hidden: true} -->\n# Backup"
+
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	if v := m.View(); !strings.Contains(v.Content, "1 of 2") {
+		t.Errorf("paging should count visible slides only:\n%s", v.Content)
+	}
+	newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: 'l'}))
+	m = newModel.(Model)
+	if m.state.SlideIndex != 2 {
+		t.Errorf("'l' should skip the hidden slide, got slide %d", m.state.SlideIndex)
+	}
+
+	// Search still reaches hidden slides.
+	for _, key := range []rune{'/', 'B', 'a', 'c', 'k'} {
+		newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: key}))
+		m = newModel.(Model)
+	}
+	newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: tea.KeyEnter}))
+	m = newModel.(Model)
+	if m.state.SlideIndex != 3 {
+		t.Errorf("search should reach the hidden slide, got slide %d", m.state.SlideIndex)
+	}
+
+	shown := NewWithOptions(content, Options{ShowHidden: true})
+	newModel, _ = shown.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	shown = newModel.(Model)
+	newModel, _ = shown.Update(tea.KeyPressMsg(tea.Key{Code: 'l'}))
+	shown = newModel.(Model)
+	if shown.state.SlideIndex != 1 {
+		t.Errorf("with ShowHidden 'l' should go to slide 1, got %d", shown.state.SlideIndex)
+	}
+	if v := shown.View(); !strings.Contains(v.Content, "2 of 4") {
+		t.Errorf("with ShowHidden paging should count every slide:\n%s", v.Content)
+	}
+}
+
+func TestModelSections(t *testing.T) {
+	content := "---\nfooter: \"{section} · {section_slide}/{section_total}\"\n---\n# Intro\n---\n" +
+		"<!-- section: Architecture -->\n# Overview\n---\n# Details\n---\n<!-- section: Rollout -->\n# Plan"
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	press := func(keys ...rune) {
+		for _, key := range keys {
+			newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: key}))
+			m = newModel.(Model)
+		}
+	}
+
+	press(']', ']')
+	if m.state.SlideIndex != 1 {
+		t.Fatalf("']]' should go to the first section, got slide %d", m.state.SlideIndex)
+	}
+	press('l')
+	if v := m.View(); !strings.Contains(v.Content, "Architecture · 2/2") {
+		t.Errorf("footer should show the section position:\n%s", v.Content)
+	}
+	press(']', ']')
+	if m.state.SlideIndex != 3 {
+		t.Errorf("']]' should go to the next section, got slide %d", m.state.SlideIndex)
+	}
+	press('[', '[')
+	if m.state.SlideIndex != 1 {
+		t.Errorf("'[[' at a section start should go to the previous section, got slide %d", m.state.SlideIndex)
+	}
+}
+
+func TestModelSpeakerNotes(t *testing.T) {
+	content := "# Intro\n\n<!-- speaker_note\n- **Greet** the room\n- Ask a question\n-->\n---\n# No notes"
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	press := func(key rune) {
+		newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: key}))
+		m = newModel.(Model)
+	}
+
+	v := m.View()
+	if strings.Contains(v.Content, "Greet") {
+		t.Errorf("notes should be hidden by default, got:\n%s", v.Content)
+	}
+	height := strings.Count(v.Content, "\n")
+
+	press('s')
+	v = m.View()
+	plain := ansi.Strip(v.Content)
+	if !strings.Contains(plain, "Greet the room") || strings.Contains(plain, "**Greet**") {
+		t.Errorf("'s' should show the notes rendered as markdown, got:\n%s", plain)
+	}
+	if got := strings.Count(v.Content, "\n"); got != height {
+		t.Errorf("view has %d lines with notes shown, want %d", got, height)
+	}
+
+	press('l')
+	if v := m.View(); strings.Contains(v.Content, "Greet") {
+		t.Errorf("slide without notes should show none, got:\n%s", v.Content)
+	}
+
+	press('s')
+	if m.showNotes {
+		t.Error("second 's' should hide the notes")
+	}
+}
+
+func TestModelSwitchLanguage(t *testing.T) {
+	content := "---\nlang: en\n---\n# Intro\n---\n<!-- lang: en -->\n# Steps\n\n- one\n<!-- pause -->\n- two\n<!-- end_lang -->\n" +
+		"<!-- lang: es -->\n# Pasos\n\n- uno\n<!-- pause -->\n- dos\n<!-- end_lang -->"
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	press := func(key rune) {
+		newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: key, Text: string(key)}))
+		m = newModel.(Model)
+	}
+
+	press('l')
+	press('l')
+	if m.state.SlideIndex != 1 || m.state.ChunkIndex != 1 {
+		t.Fatalf("state = slide %d step %d, want slide 1 step 1", m.state.SlideIndex, m.state.ChunkIndex)
+	}
+
+	press('L')
+	if m.state.SlideIndex != 1 || m.state.ChunkIndex != 1 {
+		t.Errorf("switching language moved to slide %d step %d", m.state.SlideIndex, m.state.ChunkIndex)
+	}
+	if v := m.View(); !strings.Contains(v.Content, "Pasos") || !strings.Contains(v.Content, "dos") || strings.Contains(v.Content, "Steps") {
+		t.Errorf("'L' should switch to Spanish:\n%s", v.Content)
+	}
+
+	press('L')
+	if v := m.View(); !strings.Contains(v.Content, "Steps") {
+		t.Errorf("'L' should cycle back to English:\n%s", v.Content)
+	}
+}
+
+func TestModelFrontmatterProblems(t *testing.T) {
+	m := New("---\nfootr: \"{current_slide}\"\n---\n# One", "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 24})
+	m = newModel.(Model)
+
+	if got := m.FrontmatterProblems(); len(got) != 1 || got[0].Code != "frontmatter-key" {
+		t.Fatalf("problems = %v, want one frontmatter-key", got)
+	}
+	if v := m.View(); !strings.Contains(v.Content, `line 2: unknown frontmatter key "footr"; did you mean "footer"?`) {
+		t.Errorf("the problem should replace the footer:\n%s", v.Content)
+	}
+
+	newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: tea.KeyEscape}))
+	m = newModel.(Model)
+	if v := m.View(); strings.Contains(v.Content, "footr") {
+		t.Errorf("esc should dismiss the problem:\n%s", v.Content)
+	}
+}
+
+func TestModelFootnotes(t *testing.T) {
+	content := "# Results\n\nFirst claim[^a].\n<!-- pause -->\nSecond claim[^b].\n\n[^a]: Smith, 2021.\n[^b]: Jones, 2023."
+	m := New(content, "")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = newModel.(Model)
+
+	v := m.View()
+	plain := ansi.Strip(v.Content)
+	if !strings.Contains(plain, "claim¹") || !strings.Contains(plain, "¹ Smith, 2021.") || strings.Contains(plain, "Jones") {
+		t.Errorf("first step should show only the first footnote, got:\n%s", plain)
+	}
+	lines := strings.Split(plain, "\n")
+	if got := lines[len(lines)-3]; !strings.Contains(got, "Smith") {
+		t.Errorf("footnotes should sit above the footer, got %q above it", got)
+	}
+	height := strings.Count(v.Content, "\n")
+
+	newModel, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: 'l'}))
+	m = newModel.(Model)
+	v = m.View()
+	if !strings.Contains(ansi.Strip(v.Content), "² Jones, 2023.") {
+		t.Errorf("second step should add the second footnote, got:\n%s", ansi.Strip(v.Content))
+	}
+	if got := strings.Count(v.Content, "\n"); got != height {
+		t.Errorf("view has %d lines, want %d", got, height)
+	}
+}
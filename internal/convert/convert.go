@@ -0,0 +1,357 @@
+// Package convert translates decks written for other markdown slide tools
+// into deck's format, warning about what it cannot translate.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Format names a markdown slide format Convert reads.
+type Format string
+
+const (
+	FormatSlides     Format = "slides"     // maaslalani/slides
+	FormatPresenterm Format = "presenterm" // presenterm
+	FormatMarp       Format = "marp"       // Marp
+	FormatReveal     Format = "reveal"     // reveal.js markdown and reveal-md
+)
+
+// Formats lists every format Convert reads.
+var Formats = []Format{FormatSlides, FormatPresenterm, FormatMarp, FormatReveal}
+
+var (
+	commentLineRegex = regexp.MustCompile(`^\s*<!--\s*(.*?)\s*-->\s*$`)
+	fenceRegex       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+	ruleRegex        = regexp.MustCompile(`^ {0,3}(?:-(?:[ \t]*-){2,}|\*(?:[ \t]*\*){2,}|_(?:[ \t]*_){2,})[ \t]*$`)
+	setextRegex      = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	listItemRegex    = regexp.MustCompile(`^ {0,3}([-*+]|\d{1,9}[.)])(?:[ \t]|$)`)
+)
+
+// line is a line of the source with its 1-based line number.
+type line struct {
+	n    int
+	text string
+}
+
+// field is a top-level frontmatter key and its value.
+type field struct {
+	key   string
+	value *yaml.Node
+	line  int
+}
+
+// slide is a converted slide: its slide: options and body.
+type slide struct {
+	meta  []string // "key: value" options for its slide: comment, in order
+	lines []string
+}
+
+// setMeta sets a slide option, replacing an earlier value for key.
+func (s *slide) setMeta(key, value string) {
+	for i, kv := range s.meta {
+		if strings.HasPrefix(kv, key+": ") {
+			s.meta[i] = key + ": " + value
+			return
+		}
+	}
+	s.meta = append(s.meta, key+": "+value)
+}
+
+// converter holds the state of one Convert call.
+type converter struct {
+	fm    model.Frontmatter
+	diags []model.Diagnostic
+}
+
+// warn records a warning about source line n.
+func (c *converter) warn(n int, code, format string, args ...any) {
+	c.diags = append(c.diags, model.Diagnostic{
+		Severity: model.SeverityWarning,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Span:     model.Span{StartLine: n, EndLine: n},
+	})
+}
+
+// unsupported warns that something at line n was dropped.
+func (c *converter) unsupported(n int, format string, args ...any) {
+	c.warn(n, "unsupported", format+"; it was dropped", args...)
+}
+
+// approximated warns that something at line n was translated to the
+// nearest deck feature, which behaves differently.
+func (c *converter) approximated(n int, format string, args ...any) {
+	c.warn(n, "approximated", format, args...)
+}
+
+// formatFunc converts the frontmatter fields and body lines of one format
+// into slides, setting c.fm.
+type formatFunc func(c *converter, fields []field, body []line) []slide
+
+var formats = map[Format]formatFunc{
+	FormatSlides:     convertSlides,
+	FormatPresenterm: convertPresenterm,
+	FormatMarp:       convertMarp,
+	FormatReveal:     convertReveal,
+}
+
+// Convert translates content written in format from into a deck. The
+// returned diagnostics warn about what could not be translated exactly,
+// with line numbers in content.
+func Convert(content string, from Format) (string, []model.Diagnostic, error) {
+	convert, ok := formats[from]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown format %q", from)
+	}
+
+	c := &converter{}
+	fields, body, err := splitFrontmatter(strings.ReplaceAll(content, "\r\n", "\n"))
+	if err != nil {
+		return "", nil, err
+	}
+	slides := convert(c, fields, body)
+	return encodeFrontmatter(c.fm) + encodeSlides(slides), c.diags, nil
+}
+
+// Detect guesses the format of content from the syntax it uses, falling
+// back to slides, whose format is closest to deck's.
+func Detect(content string) Format {
+	fields, body, _ := splitFrontmatter(strings.ReplaceAll(content, "\r\n", "\n"))
+	for _, f := range fields {
+		switch f.key {
+		case "marp":
+			return FormatMarp
+		case "sub_title", "options":
+			return FormatPresenterm
+		case "revealOptions", "verticalSeparator":
+			return FormatReveal
+		}
+	}
+	for _, l := range body {
+		switch {
+		case strings.Contains(l.text, "<!-- end_slide -->"):
+			return FormatPresenterm
+		case noteLineRegex.MatchString(l.text), strings.Contains(l.text, "<!-- .element:"),
+			strings.Contains(l.text, "<!-- .slide:"):
+			return FormatReveal
+		}
+	}
+	return FormatSlides
+}
+
+// splitFrontmatter returns the top-level fields of content's YAML
+// frontmatter, if any, and the lines after it.
+func splitFrontmatter(content string) ([]field, []line, error) {
+	texts := strings.Split(content, "\n")
+	lines := make([]line, len(texts))
+	for i, text := range texts {
+		lines[i] = line{n: i + 1, text: text}
+	}
+	if len(texts) == 0 || strings.TrimRight(texts[0], " \t") != "---" {
+		return nil, lines, nil
+	}
+	end := -1
+	for i := 1; i < len(texts); i++ {
+		if t := strings.TrimRight(texts[i], " \t"); t == "---" || t == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, lines, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(texts[1:end], "\n")), &doc); err != nil {
+		return nil, nil, fmt.Errorf("frontmatter: %w", err)
+	}
+	var fields []field
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		m := doc.Content[0].Content
+		for i := 0; i+1 < len(m); i += 2 {
+			fields = append(fields, field{key: m[i].Value, value: m[i+1], line: m[i].Line + 1})
+		}
+	}
+	return fields, lines[end+1:], nil
+}
+
+// encodeFrontmatter renders the fields of fm that are set as a frontmatter
+// block, or "" when none are.
+func encodeFrontmatter(fm model.Frontmatter) string {
+	var doc yaml.Node
+	if err := doc.Encode(fm); err != nil || doc.Kind != yaml.MappingNode {
+		return ""
+	}
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if v := doc.Content[i+1]; !isZero(v) {
+			kept = append(kept, doc.Content[i], v)
+		}
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	doc.Content = kept
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return ""
+	}
+	return "---\n" + string(out) + "---\n\n"
+}
+
+// isZero reports whether an encoded frontmatter value is unset.
+func isZero(v *yaml.Node) bool {
+	switch v.Kind {
+	case yaml.ScalarNode:
+		return v.Value == "" || v.Value == "0" || v.Value == "false" || v.Tag == "!!null"
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(v.Content) == 0
+	}
+	return false
+}
+
+// encodeSlides joins the slides with --- separators, trimming blank lines
+// around each slide and opening it with its slide: comment.
+func encodeSlides(slides []slide) string {
+	parts := make([]string, 0, len(slides))
+	for _, s := range slides {
+		body := strings.Trim(strings.Join(squeeze(s.lines), "\n"), "\n")
+		if len(s.meta) > 0 {
+			body = strings.TrimRight("<!-- slide: {"+strings.Join(s.meta, ", ")+"} -->\n\n"+body, "\n")
+		}
+		parts = append(parts, body)
+	}
+	return strings.Join(parts, "\n\n---\n\n") + "\n"
+}
+
+// squeeze collapses runs of blank lines outside code, which dropped
+// comments and directives leave behind.
+func squeeze(lines []string) []string {
+	var out []string
+	var f fence
+	for _, text := range lines {
+		blank := strings.TrimSpace(text) == ""
+		if !f.code(text) && blank && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			continue
+		}
+		out = append(out, text)
+	}
+	return out
+}
+
+// noteComment renders a speaker note, as a block when it spans lines.
+func noteComment(note string) string {
+	note = strings.TrimSpace(note)
+	if !strings.Contains(note, "\n") {
+		return "<!-- speaker_note: " + note + " -->"
+	}
+	return "<!-- speaker_note\n" + note + "\n-->"
+}
+
+// fence tracks fenced code blocks while walking lines.
+type fence struct {
+	marker string // the open fence's backticks or tildes, or ""
+}
+
+// code reports whether text opens, closes or is inside a fenced code
+// block.
+func (f *fence) code(text string) bool {
+	m := fenceRegex.FindStringSubmatch(text)
+	if f.marker != "" {
+		if m != nil && m[1][0] == f.marker[0] && len(m[1]) >= len(f.marker) && strings.TrimSpace(m[2]) == "" {
+			f.marker = ""
+		}
+		return true
+	}
+	if m != nil {
+		f.marker = m[1]
+		return true
+	}
+	return false
+}
+
+// comment returns the text inside an HTML comment that starts body[i],
+// which may run over several lines, and the index of its last line.
+func comment(body []line, i int) (inner string, last int, ok bool) {
+	if m := commentLineRegex.FindStringSubmatch(body[i].text); m != nil {
+		return m[1], i, true
+	}
+	start := strings.TrimSpace(body[i].text)
+	if !strings.HasPrefix(start, "<!--") || strings.Contains(start, "-->") {
+		return "", i, false
+	}
+	parts := []string{strings.TrimPrefix(start, "<!--")}
+	for j := i + 1; j < len(body); j++ {
+		text := body[j].text
+		if before, _, found := strings.Cut(text, "-->"); found {
+			if strings.TrimSpace(text[len(before)+3:]) != "" {
+				return "", i, false
+			}
+			parts = append(parts, before)
+			return strings.TrimSpace(strings.Join(parts, "\n")), j, true
+		}
+		parts = append(parts, text)
+	}
+	return "", i, false
+}
+
+// splitAt splits body into slides at the lines matching sep outside code.
+func splitAt(body []line, sep func(text string) bool) [][]line {
+	var slides [][]line
+	var f fence
+	start := 0
+	for i, l := range body {
+		if f.code(l.text) {
+			continue
+		}
+		if sep(l.text) {
+			slides = append(slides, body[start:i])
+			start = i + 1
+		}
+	}
+	return append(slides, body[start:])
+}
+
+// atxHeading converts a setext heading, text underlined with = or -, to
+// an ATX heading, so a - underline is not read as a slide separator.
+// It reports false when underline does not close a setext heading after
+// text.
+func atxHeading(text, underline string) (string, bool) {
+	m := setextRegex.FindStringSubmatch(underline)
+	if m == nil || strings.TrimSpace(text) == "" || listItemRegex.MatchString(text) ||
+		strings.HasPrefix(strings.TrimSpace(text), "#") || strings.HasPrefix(strings.TrimSpace(text), ">") ||
+		strings.HasPrefix(strings.TrimSpace(text), "<") || strings.HasPrefix(strings.TrimSpace(text), "|") {
+		return "", false
+	}
+	level := "#"
+	if m[1][0] == '-' {
+		level = "##"
+	}
+	return level + " " + strings.TrimSpace(text), true
+}
+
+// yamlString returns a scalar frontmatter value, or "" for other nodes.
+func yamlString(v *yaml.Node) string {
+	if v.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return v.Value
+}
+
+// quote renders s as a YAML flow scalar for a slide: comment.
+func quote(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return `""`
+	}
+	q := strings.TrimSuffix(string(out), "\n")
+	if strings.ContainsAny(q, ",{}[]") && !strings.HasPrefix(q, `"`) && !strings.HasPrefix(q, "'") {
+		return `"` + strings.ReplaceAll(q, `"`, `\"`) + `"`
+	}
+	return q
+}
@@ -0,0 +1,141 @@
+package convert
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jedwards1230/deck/internal/parse"
+)
+
+var update = flag.Bool("update", false, "rewrite the testdata/*.deck.md golden files")
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		from      Format
+		wantDiags []string // code:line of each warning, in order
+		wantNotes int      // speaker notes across the converted deck
+		steps     int      // reveal steps after each slide's first
+		slides    int
+	}{
+		{
+			from:      FormatSlides,
+			wantDiags: []string{"unsupported:2", "approximated:22"},
+			slides:    3,
+		},
+		{
+			from: FormatPresenterm,
+			wantDiags: []string{
+				"unsupported:6", "approximated:45", "approximated:51", "unsupported:53",
+			},
+			wantNotes: 2,
+			steps:     2,
+			slides:    5,
+		},
+		{
+			from:      FormatMarp,
+			wantDiags: []string{"unsupported:3", "unsupported:27", "unsupported:39"},
+			wantNotes: 2,
+			steps:     1,
+			slides:    4,
+		},
+		{
+			from: FormatReveal,
+			wantDiags: []string{
+				"unsupported:2", "unsupported:3", "unsupported:4",
+				"unsupported:14", "approximated:19", "approximated:25",
+			},
+			wantNotes: 3,
+			steps:     2,
+			slides:    4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from), func(t *testing.T) {
+			src, err := os.ReadFile(filepath.Join("testdata", string(tt.from)+".md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, diags, err := Convert(string(src), tt.from)
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+
+			golden := filepath.Join("testdata", string(tt.from)+".deck.md")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("Convert output differs from %s:\n%s", golden, got)
+			}
+
+			var codes []string
+			for _, d := range diags {
+				codes = append(codes, fmt.Sprintf("%s:%d", d.Code, d.Span.StartLine))
+			}
+			if strings.Join(codes, " ") != strings.Join(tt.wantDiags, " ") {
+				t.Errorf("diagnostics = %v, want %v\n%v", codes, tt.wantDiags, diags)
+			}
+
+			// The converted deck must read back cleanly.
+			pres := parse.ParsePresentation(got)
+			if len(pres.Diagnostics) != 0 {
+				t.Errorf("converted deck has diagnostics: %v", pres.Diagnostics)
+			}
+			if len(pres.Slides) != tt.slides {
+				t.Errorf("converted deck has %d slides, want %d", len(pres.Slides), tt.slides)
+			}
+			notes, steps := 0, 0
+			for _, s := range pres.Slides {
+				notes += len(s.SpeakerNotes)
+				steps += len(s.Chunks) - 1
+			}
+			if notes != tt.wantNotes {
+				t.Errorf("converted deck has %d speaker notes, want %d", notes, tt.wantNotes)
+			}
+			if steps != tt.steps {
+				t.Errorf("converted deck has %d steps, want %d", steps, tt.steps)
+			}
+		})
+	}
+}
+
+func TestConvertUnknownFormat(t *testing.T) {
+	if _, _, err := Convert("# One", "keynote"); err == nil {
+		t.Error("Convert with an unknown format should fail")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Format
+	}{
+		{"plain markdown", "# One\n\n---\n\n# Two", FormatSlides},
+		{"marp frontmatter", "---\nmarp: true\n---\n# One", FormatMarp},
+		{"presenterm frontmatter", "---\ntitle: Talk\nsub_title: More\n---\n# One", FormatPresenterm},
+		{"end_slide", "# One\n<!-- end_slide -->\n# Two", FormatPresenterm},
+		{"reveal notes", "# One\n\nNote: say hello", FormatReveal},
+		{"reveal fragment", "- a <!-- .element: class=\"fragment\" -->", FormatReveal},
+		{"reveal-md frontmatter", "---\nrevealOptions:\n  transition: fade\n---\n# One", FormatReveal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.content); got != tt.want {
+				t.Errorf("Detect = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
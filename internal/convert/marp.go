@@ -0,0 +1,234 @@
+package convert
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	marpBackgroundRegex = regexp.MustCompile(`^\s*!\[bg\b[^\]]*\]\([^)]*\)\s*$`)
+	marpFragmentRegex   = regexp.MustCompile(`^\s*(?:\*|\d{1,9}\))[ \t]`)
+	marpListRegex       = regexp.MustCompile(`^\s*(?:[-+*]|\d{1,9}[.)])[ \t]`)
+	atxRegex            = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]|$)`)
+)
+
+// marpDirectives lists Marp's directives, which set slide options in the
+// frontmatter or in comments instead of being speaker notes.
+var marpDirectives = map[string]bool{
+	"theme": true, "style": true, "headingDivider": true, "size": true, "math": true,
+	"title": true, "description": true, "author": true, "keywords": true, "url": true,
+	"image": true, "lang": true, "marp": true, "transition": true,
+	"paginate": true, "header": true, "footer": true, "class": true,
+	"backgroundColor": true, "backgroundImage": true, "backgroundPosition": true,
+	"backgroundRepeat": true, "backgroundSize": true, "color": true,
+}
+
+// marpState holds the local directives in effect, which carry over to the
+// slides that follow the one setting them.
+type marpState struct {
+	class    string
+	paginate bool
+}
+
+// convertMarp converts a Marp deck. Horizontal rules separate its slides;
+// directives become slide options, other comments speaker notes, and
+// lists marked with * or 1) reveal item by item.
+func convertMarp(c *converter, fields []field, body []line) []slide {
+	var local marpState
+	divider := 0 // heading level that starts a slide, or 0
+	for _, f := range fields {
+		switch f.key {
+		case "marp":
+		case "author":
+			c.fm.Author = yamlString(f.value)
+		case "footer":
+			c.fm.Footer = yamlString(f.value)
+		case "headingDivider":
+			if n, err := strconv.Atoi(yamlString(f.value)); err == nil && n >= 1 && n <= 6 {
+				divider = n
+			} else {
+				c.unsupported(f.line, "headingDivider %s is not supported; only a single level is", yamlString(f.value))
+			}
+		default:
+			c.marpDirective(&local, f.key, f.value, f.line)
+		}
+	}
+
+	var slides []slide
+	var paginate []bool
+	for _, raw := range splitMarp(body, divider) {
+		scoped := local
+		s := c.marpSlide(raw, &local, &scoped)
+		if scoped.class == "lead" {
+			s.setMeta("class", "centered")
+		}
+		slides = append(slides, s)
+		paginate = append(paginate, scoped.paginate)
+	}
+
+	// Paging is deck-wide, so slides without it hide their footer.
+	for _, p := range paginate {
+		if p {
+			c.fm.Paging = "%d / %d"
+			break
+		}
+	}
+	if c.fm.Paging != "" {
+		for i, p := range paginate {
+			if !p {
+				slides[i].setMeta("footer", "false")
+			}
+		}
+	}
+	return slides
+}
+
+// marpDirective applies a directive to state, warning about those deck
+// cannot express.
+func (c *converter) marpDirective(state *marpState, key string, value *yaml.Node, n int) {
+	switch key {
+	case "class":
+		if v := yamlString(value); v != "lead" && v != "" {
+			c.unsupported(n, "class %q is not supported", v)
+			state.class = ""
+		} else {
+			state.class = v
+		}
+	case "paginate":
+		state.paginate = yamlString(value) == "true"
+	default:
+		c.unsupported(n, "directive %s is not supported", key)
+	}
+}
+
+// splitMarp splits body into slides at horizontal rules outside code, and
+// before headings of level divider or higher when it is not 0. A --- under
+// a line of text underlines a heading instead.
+func splitMarp(body []line, divider int) [][]line {
+	var slides [][]line
+	var f fence
+	start := 0
+	for i, l := range body {
+		if f.code(l.text) {
+			continue
+		}
+		if divider > 0 && hasText(body[start:i]) && headingLevel(body, i) <= divider {
+			slides = append(slides, body[start:i])
+			start = i
+		}
+		if !ruleRegex.MatchString(l.text) {
+			continue
+		}
+		if i > start {
+			if _, ok := atxHeading(body[i-1].text, l.text); ok {
+				continue
+			}
+		}
+		slides = append(slides, body[start:i])
+		start = i + 1
+	}
+	return append(slides, body[start:])
+}
+
+// hasText reports whether any of lines holds more than blanks and
+// single-line comments.
+func hasText(lines []line) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l.text) != "" && !commentLineRegex.MatchString(l.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// headingLevel returns the level of a heading starting at body[i], or 7
+// when there is none.
+func headingLevel(body []line, i int) int {
+	if m := atxRegex.FindStringSubmatch(body[i].text); m != nil {
+		return len(m[1])
+	}
+	if i+1 < len(body) {
+		if heading, ok := atxHeading(body[i].text, body[i+1].text); ok {
+			return strings.Index(heading, " ")
+		}
+	}
+	return 7
+}
+
+// marpSlide converts the body of one slide, applying its directive
+// comments to local and scoped and turning its other comments into
+// speaker notes.
+func (c *converter) marpSlide(raw []line, local, scoped *marpState) slide {
+	var s slide
+	var f fence
+	fragments, others := false, false
+	for i := 0; i < len(raw); i++ {
+		l := raw[i]
+		if f.code(l.text) {
+			s.lines = append(s.lines, l.text)
+			continue
+		}
+		if i+1 < len(raw) {
+			if heading, ok := atxHeading(l.text, raw[i+1].text); ok {
+				s.lines = append(s.lines, heading)
+				i++
+				continue
+			}
+		}
+		if marpBackgroundRegex.MatchString(l.text) {
+			c.unsupported(l.n, "background image %s is not supported", strings.TrimSpace(l.text))
+			continue
+		}
+		if marpFragmentRegex.MatchString(l.text) {
+			fragments = true
+		} else if marpListRegex.MatchString(l.text) {
+			others = true
+		}
+
+		inner, last, ok := comment(raw, i)
+		if !ok {
+			s.lines = append(s.lines, l.text)
+			continue
+		}
+		if !c.marpComment(inner, l.n, local, scoped) {
+			s.lines = append(s.lines, noteComment(inner))
+		}
+		i = last
+	}
+
+	if fragments {
+		s.setMeta("incremental_lists", "true")
+		if others {
+			c.approximated(raw[0].n, "only lists marked with * or 1) are fragmented in Marp; every list on the slide is revealed item by item")
+		}
+	}
+	return s
+}
+
+// marpComment applies the directives in a comment, reporting false when it
+// holds anything else and is a speaker note.
+func (c *converter) marpComment(inner string, n int, local, scoped *marpState) bool {
+	var doc yaml.Node
+	if yaml.Unmarshal([]byte(inner), &doc) != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return false
+	}
+	m := doc.Content[0].Content
+	for i := 0; i+1 < len(m); i += 2 {
+		if !marpDirectives[strings.TrimPrefix(m[i].Value, "_")] {
+			return false
+		}
+	}
+	for i := 0; i+1 < len(m); i += 2 {
+		key := m[i].Value
+		if scopedKey, ok := strings.CutPrefix(key, "_"); ok {
+			c.marpDirective(scoped, scopedKey, m[i+1], n+m[i].Line-1)
+			continue
+		}
+		c.marpDirective(local, key, m[i+1], n+m[i].Line-1)
+		(&converter{}).marpDirective(scoped, key, m[i+1], 0) // warned once above
+	}
+	return true
+}
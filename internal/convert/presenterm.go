@@ -0,0 +1,274 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	presentermEndRegex       = regexp.MustCompile(`^end_slide$`)
+	presentermNewLinesRegex  = regexp.MustCompile(`^new_lines:\s*(\d+)$`)
+	presentermAlignmentRegex = regexp.MustCompile(`^alignment:\s*(left|center|right)$`)
+	presentermListsRegex     = regexp.MustCompile(`^incremental_lists:\s*(true|false)$`)
+	commandShapeRegex        = regexp.MustCompile(`^[a-z][a-z0-9_]*\s*(:|$)`)
+)
+
+// presentermOptions are the settings of a presenterm deck's options
+// frontmatter that change how its markdown is read.
+type presentermOptions struct {
+	implicitEnds  bool   // a slide title starts a new slide
+	shorthand     bool   // --- ends a slide
+	commandPrefix string // prefix of every command comment
+}
+
+// convertPresenterm converts a presenterm deck. Its pause, column and
+// speaker note commands are deck's own; end_slide becomes ---, and the
+// title, sub_title, event and location fields become an opening slide.
+func convertPresenterm(c *converter, fields []field, body []line) []slide {
+	var opts presentermOptions
+	var intro slide
+	for _, f := range fields {
+		switch f.key {
+		case "title":
+			intro.lines = append(intro.lines, "# "+yamlString(f.value), "")
+		case "sub_title", "event", "location":
+			intro.lines = append(intro.lines, yamlString(f.value), "")
+		case "author":
+			c.fm.Author = yamlString(f.value)
+		case "authors":
+			var authors []string
+			for _, a := range f.value.Content {
+				authors = append(authors, yamlString(a))
+			}
+			c.fm.Author = strings.Join(authors, ", ")
+		case "date":
+			c.fm.Date = yamlString(f.value)
+		case "options":
+			opts = c.presentermOptions(f.value.Content)
+		default:
+			c.unsupported(f.line, "frontmatter %s is not supported", f.key)
+		}
+	}
+
+	var slides []slide
+	if len(intro.lines) > 0 {
+		intro.setMeta("class", "centered")
+		slides = append(slides, intro)
+	}
+	for _, raw := range splitPresenterm(body, opts) {
+		slides = append(slides, c.presentermSlide(raw, opts))
+	}
+	return slides
+}
+
+// presentermOptions reads the options frontmatter mapping.
+func (c *converter) presentermOptions(m []*yaml.Node) presentermOptions {
+	var opts presentermOptions
+	for i := 0; i+1 < len(m); i += 2 {
+		key, value := m[i].Value, m[i+1].Value
+		switch key {
+		case "implicit_slide_ends":
+			opts.implicitEnds = value == "true"
+		case "end_slide_shorthand":
+			opts.shorthand = value == "true"
+		case "command_prefix":
+			opts.commandPrefix = value
+		case "incremental_lists":
+			c.fm.IncrementalLists = value == "true"
+		default:
+			c.unsupported(m[i].Line+1, "option %s is not supported", key)
+		}
+	}
+	return opts
+}
+
+// command returns the command in a comment's inner text, without the
+// command prefix, or false for comments that are not commands.
+func (o presentermOptions) command(inner string) (string, bool) {
+	if o.commandPrefix == "" {
+		return inner, true
+	}
+	return strings.CutPrefix(inner, o.commandPrefix)
+}
+
+// splitPresenterm splits body into slides at end_slide commands, and at
+// --- breaks and slide titles when the options say so.
+func splitPresenterm(body []line, opts presentermOptions) [][]line {
+	var slides [][]line
+	var f fence
+	start := 0
+	for i, l := range body {
+		if f.code(l.text) {
+			continue
+		}
+		end := false
+		if m := commentLineRegex.FindStringSubmatch(l.text); m != nil {
+			cmd, ok := opts.command(m[1])
+			end = ok && presentermEndRegex.MatchString(cmd)
+		}
+		if opts.shorthand && l.text == "---" && (i == 0 || strings.TrimSpace(body[i-1].text) == "") {
+			end = true
+		}
+		if end {
+			slides = append(slides, body[start:i])
+			start = i + 1
+			continue
+		}
+		if opts.implicitEnds && i+1 < len(body) && hasContent(body[start:i]) {
+			if _, ok := atxHeading(l.text, body[i+1].text); ok && !(opts.shorthand && body[i+1].text == "---") {
+				slides = append(slides, body[start:i])
+				start = i
+			}
+		}
+	}
+	return append(slides, body[start:])
+}
+
+// hasContent reports whether any of lines is not blank.
+func hasContent(lines []line) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l.text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// presentermSlide converts the body of one slide.
+func (c *converter) presentermSlide(raw []line, opts presentermOptions) slide {
+	var s slide
+	var f fence
+	started := false // whether the slide has content yet
+	listed := false  // whether it has a list yet
+	for i := 0; i < len(raw); i++ {
+		l := raw[i]
+		if outside := f.marker == ""; f.code(l.text) {
+			if outside {
+				s.lines = append(s.lines, c.presentermFence(l))
+			} else {
+				s.lines = append(s.lines, l.text)
+			}
+			started = true
+			continue
+		}
+
+		if i+1 < len(raw) {
+			if heading, ok := atxHeading(l.text, raw[i+1].text); ok {
+				s.lines = append(s.lines, heading)
+				started = true
+				i++
+				continue
+			}
+		}
+		if ruleRegex.MatchString(l.text) && strings.TrimSpace(l.text)[0] == '-' {
+			s.lines = append(s.lines, "***") // --- would end the slide
+			continue
+		}
+
+		inner, last, ok := comment(raw, i)
+		if !ok {
+			s.lines = append(s.lines, l.text)
+			started = started || strings.TrimSpace(l.text) != ""
+			listed = listed || listItemRegex.MatchString(l.text)
+			continue
+		}
+		cmd, isCmd := opts.command(inner)
+		if !isCmd {
+			s.lines = append(s.lines, textOf(raw[i:last+1])...)
+			i = last
+			continue
+		}
+		s.lines = append(s.lines, c.presentermCommand(&s, cmd, raw[i:last+1], started, listed)...)
+		i = last
+	}
+	return s
+}
+
+// presentermCommand translates one command comment spanning src, returning
+// the lines that replace it. started and listed report whether content and
+// lists come before it on the slide.
+func (c *converter) presentermCommand(s *slide, cmd string, src []line, started, listed bool) []string {
+	n := src[0].n
+	name, _, _ := strings.Cut(cmd, ":")
+	switch {
+	case cmd == "pause", cmd == "reset_layout", name == "column_layout", name == "column", name == "include":
+		return []string{"<!-- " + cmd + " -->"}
+	case name == "speaker_note":
+		// A note may be a YAML block scalar: speaker_note: |
+		var note struct {
+			Text string `yaml:"speaker_note"`
+		}
+		if yaml.Unmarshal([]byte(cmd), &note) != nil || note.Text == "" {
+			note.Text = strings.TrimPrefix(cmd, "speaker_note:")
+		}
+		return []string{noteComment(note.Text)}
+	case cmd == "jump_to_middle":
+		c.approximated(n, "jump_to_middle centers the whole slide vertically")
+		return []string{"<!-- valign: middle -->"}
+	case cmd == "new_line", cmd == "newline":
+		c.approximated(n, "%s is a blank line, which adds no space between blocks", cmd)
+		return []string{""}
+	case presentermNewLinesRegex.MatchString(cmd):
+		c.approximated(n, "new_lines is a blank line, which adds no space between blocks")
+		return []string{""}
+	case presentermAlignmentRegex.MatchString(cmd):
+		align := presentermAlignmentRegex.FindStringSubmatch(cmd)[1]
+		if started {
+			c.approximated(n, "alignment aligns the whole slide, not just the content after it")
+		}
+		return []string{"<!-- align: " + align + " -->"}
+	case presentermListsRegex.MatchString(cmd):
+		if listed {
+			c.approximated(n, "incremental_lists applies to every list on the slide")
+		}
+		s.setMeta("incremental_lists", presentermListsRegex.FindStringSubmatch(cmd)[1])
+		return nil
+	case cmd == "no_footer":
+		s.setMeta("footer", "false")
+		return nil
+	case cmd == "skip_slide":
+		s.setMeta("hidden", "true")
+		return nil
+	case !commandShapeRegex.MatchString(cmd):
+		return textOf(src) // an ordinary comment
+	default:
+		c.unsupported(n, "command %q is not supported", name)
+		return nil
+	}
+}
+
+// presentermFence strips presenterm's +attributes from an opening code
+// fence, keeping the language.
+func (c *converter) presentermFence(l line) string {
+	m := fenceRegex.FindStringSubmatch(l.text)
+	words := strings.Fields(m[2])
+	if len(words) == 0 {
+		return l.text
+	}
+	kept := []string{words[0]}
+	for _, w := range words[1:] {
+		switch {
+		case w == "+exec":
+			// ctrl+e runs any block in a supported language
+		case w == "+exec_replace":
+			c.approximated(l.n, "+exec_replace blocks run with ctrl+e and show their output below the code")
+		case strings.HasPrefix(w, "+"), strings.HasPrefix(w, "{"):
+			c.unsupported(l.n, "code block attribute %s is not supported", w)
+		default:
+			kept = append(kept, w)
+		}
+	}
+	indent := l.text[:strings.Index(l.text, m[1])]
+	return indent + m[1] + strings.Join(kept, " ")
+}
+
+// textOf returns the text of lines.
+func textOf(lines []line) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.text
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	noteLineRegex      = regexp.MustCompile(`^(?i)notes?:`)
+	revealElementRegex = regexp.MustCompile(`\s*<!--\s*\.element:\s*(.*?)\s*-->`)
+	revealSlideRegex   = regexp.MustCompile(`^\s*<!--\s*\.slide:\s*(.*?)\s*-->\s*$`)
+	revealAttrRegex    = regexp.MustCompile(`([\w-]+)(?:=(?:"([^"]*)"|'([^']*)'|(\S+)))?`)
+	asideOpenRegex     = regexp.MustCompile(`^\s*<aside\s+class=["']notes["']\s*>`)
+	asideCloseRegex    = regexp.MustCompile(`</aside>\s*$`)
+	separatorAnchors   = regexp.MustCompile(`^\^?(?:\\r\?)?(?:\\n)?|(?:\\r\?)?(?:\\n)?\$?$`)
+)
+
+// revealSeparators match the lines separating reveal.js slides, set by a
+// reveal-md frontmatter's separator and verticalSeparator.
+type revealSeparators struct {
+	horizontal, vertical *regexp.Regexp
+}
+
+// convertReveal converts a reveal.js markdown deck, or a reveal-md one with
+// frontmatter. Vertical slides are flattened into the horizontal order;
+// fragments become pauses, and Note: sections and notes asides speaker
+// notes.
+func convertReveal(c *converter, fields []field, body []line) []slide {
+	seps := revealSeparators{
+		horizontal: regexp.MustCompile(`^---$`),
+		vertical:   regexp.MustCompile(`^(?:--|----)$`),
+	}
+	for _, f := range fields {
+		switch f.key {
+		case "separator":
+			if re := c.revealSeparator(f); re != nil {
+				seps.horizontal = re
+			}
+		case "verticalSeparator":
+			if re := c.revealSeparator(f); re != nil {
+				seps.vertical = re
+			}
+		default:
+			c.unsupported(f.line, "frontmatter %s is not supported", f.key)
+		}
+	}
+
+	var slides []slide
+	warned := false
+	for _, raw := range splitReveal(body, seps) {
+		if raw.vertical && !warned {
+			c.approximated(raw.lines[0].n-1, "vertical slides are flattened into the horizontal order")
+			warned = true
+		}
+		slides = append(slides, c.revealSlide(raw.lines))
+	}
+	return slides
+}
+
+// revealSeparator compiles a reveal-md separator as a whole-line pattern,
+// dropping the newlines reveal.js matches around it.
+func (c *converter) revealSeparator(f field) *regexp.Regexp {
+	pattern := separatorAnchors.ReplaceAllString(yamlString(f.value), "")
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil || pattern == "" {
+		c.unsupported(f.line, "%s %q is not supported", f.key, yamlString(f.value))
+		return nil
+	}
+	return re
+}
+
+// revealRaw is the source of one reveal.js slide.
+type revealRaw struct {
+	lines    []line
+	vertical bool // whether a vertical separator opens it
+}
+
+// splitReveal splits body into slides at separators outside code. A ---
+// under a line of text underlines a heading instead.
+func splitReveal(body []line, seps revealSeparators) []revealRaw {
+	var slides []revealRaw
+	var f fence
+	start, vertical := 0, false
+	for i, l := range body {
+		if f.code(l.text) {
+			continue
+		}
+		h, v := seps.horizontal.MatchString(l.text), seps.vertical.MatchString(l.text)
+		if !h && !v {
+			continue
+		}
+		if i > start {
+			if _, ok := atxHeading(body[i-1].text, l.text); ok {
+				continue
+			}
+		}
+		slides = append(slides, revealRaw{lines: body[start:i], vertical: vertical})
+		start, vertical = i+1, v && !h
+	}
+	return append(slides, revealRaw{lines: body[start:], vertical: vertical})
+}
+
+// revealSlide converts the body of one slide.
+func (c *converter) revealSlide(raw []line) slide {
+	var s slide
+	var f fence
+	var notes []string
+	for i := 0; i < len(raw); i++ {
+		l := raw[i]
+		if f.code(l.text) {
+			s.lines = append(s.lines, l.text)
+			continue
+		}
+		if i+1 < len(raw) {
+			if heading, ok := atxHeading(l.text, raw[i+1].text); ok {
+				s.lines = append(s.lines, heading)
+				i++
+				continue
+			}
+		}
+
+		switch {
+		case noteLineRegex.MatchString(l.text):
+			// The rest of the slide is its notes.
+			note := noteLineRegex.ReplaceAllString(l.text, "")
+			notes = append(notes, strings.TrimSpace(strings.Join(append([]string{note}, textOf(raw[i+1:])...), "\n")))
+			i = len(raw)
+		case asideOpenRegex.MatchString(l.text):
+			last := i
+			for last < len(raw)-1 && !asideCloseRegex.MatchString(raw[last].text) {
+				last++
+			}
+			text := strings.Join(textOf(raw[i:last+1]), "\n")
+			text = asideCloseRegex.ReplaceAllString(asideOpenRegex.ReplaceAllString(text, ""), "")
+			notes = append(notes, text)
+			i = last
+		case revealSlideRegex.MatchString(l.text):
+			c.revealSlideAttrs(&s, revealSlideRegex.FindStringSubmatch(l.text)[1], l.n)
+		case revealElementRegex.MatchString(l.text):
+			text := revealElementRegex.ReplaceAllString(l.text, "")
+			if c.revealElementAttrs(revealElementRegex.FindStringSubmatch(l.text)[1], l.n) {
+				s.lines = append(s.lines, "<!-- pause -->")
+			}
+			s.lines = append(s.lines, text)
+		default:
+			s.lines = append(s.lines, l.text)
+		}
+	}
+
+	for _, note := range notes {
+		if note = strings.TrimSpace(note); note != "" {
+			s.lines = append(s.lines, "", noteComment(note))
+		}
+	}
+	return s
+}
+
+// revealAttrs parses the HTML attributes of a .slide or .element comment.
+func revealAttrs(attrs string) [][2]string {
+	var out [][2]string
+	for _, m := range revealAttrRegex.FindAllStringSubmatch(attrs, -1) {
+		out = append(out, [2]string{m[1], m[2] + m[3] + m[4]})
+	}
+	return out
+}
+
+// revealSlideAttrs applies a .slide comment's attributes to s.
+func (c *converter) revealSlideAttrs(s *slide, attrs string, n int) {
+	for _, a := range revealAttrs(attrs) {
+		switch {
+		case a[0] == "id":
+			s.setMeta("id", quote(a[1]))
+		case a[0] == "data-visibility" && a[1] == "hidden":
+			s.setMeta("hidden", "true")
+		default:
+			c.unsupported(n, "slide attribute %s is not supported", a[0])
+		}
+	}
+}
+
+// revealElementAttrs reports whether a .element comment's attributes make
+// its element a fragment, warning about the others.
+func (c *converter) revealElementAttrs(attrs string, n int) bool {
+	fragment := false
+	for _, a := range revealAttrs(attrs) {
+		if a[0] != "class" {
+			c.unsupported(n, "element attribute %s is not supported", a[0])
+			continue
+		}
+		for _, class := range strings.Fields(a[1]) {
+			switch {
+			case class == "fragment":
+				fragment = true
+			case strings.HasPrefix(class, "fade-"), strings.HasPrefix(class, "highlight-"),
+				class == "grow", class == "shrink", class == "strike":
+				c.approximated(n, "fragment style %s is shown as a plain pause", class)
+			default:
+				c.unsupported(n, "element class %s is not supported", class)
+			}
+		}
+	}
+	return fragment
+}
@@ -0,0 +1,39 @@
+package convert
+
+import "strings"
+
+// convertSlides converts a maaslalani/slides deck. Its separators, paging
+// template and code blocks are deck's own, so only its theme and
+// pre-processed ~~~command blocks need attention.
+func convertSlides(c *converter, fields []field, body []line) []slide {
+	for _, f := range fields {
+		switch f.key {
+		case "author":
+			c.fm.Author = yamlString(f.value)
+		case "date":
+			c.fm.Date = yamlString(f.value)
+		case "paging":
+			c.fm.Paging = yamlString(f.value)
+		default:
+			c.unsupported(f.line, "frontmatter %s is not supported", f.key)
+		}
+	}
+
+	var slides []slide
+	for _, raw := range splitAt(body, func(text string) bool { return text == "---" }) {
+		var s slide
+		var f fence
+		for _, l := range raw {
+			// slides pipes a ~~~ block through the command in its info string
+			outside := f.marker == ""
+			if f.code(l.text) && outside {
+				if m := fenceRegex.FindStringSubmatch(l.text); strings.HasPrefix(m[1], "~") && strings.TrimSpace(m[2]) != "" {
+					c.approximated(l.n, "pre-processed block %q is shown as code instead of running its command", strings.TrimSpace(m[2]))
+				}
+			}
+			s.lines = append(s.lines, l.text)
+		}
+		slides = append(slides, s)
+	}
+	return slides
+}
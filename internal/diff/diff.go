@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/jedwards1230/deck/internal/model"
@@ -30,7 +31,7 @@ func FindModified(old, new *model.Presentation) int {
 }
 
 func slideChanged(a, b model.Slide) bool {
-	if len(a.Chunks) != len(b.Chunks) {
+	if metaChanged(a.Meta, b.Meta) || len(a.Chunks) != len(b.Chunks) {
 		return true
 	}
 	for i := range a.Chunks {
@@ -43,6 +44,22 @@ func slideChanged(a, b model.Slide) bool {
 	return false
 }
 
+func metaChanged(a, b model.SlideMeta) bool {
+	return a.ID != b.ID || a.Title != b.Title || a.Class != b.Class ||
+		a.ShowFooter() != b.ShowFooter() || a.Hidden != b.Hidden ||
+		a.Section != b.Section || !slices.Equal(a.Only, b.Only) ||
+		!boolPtrEqual(a.IncrementalLists, b.IncrementalLists)
+}
+
+// boolPtrEqual reports whether two optional settings are both unset or
+// both set to the same value.
+func boolPtrEqual(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func normalizeContent(s string) string {
 	return strings.TrimSpace(s)
 }
@@ -147,6 +147,52 @@ func TestFindModified(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "slide metadata change returns that slide",
+			old: &model.Presentation{
+				Slides: []model.Slide{
+					{Chunks: []model.Chunk{{Content: "# Slide 1"}}},
+					{Chunks: []model.Chunk{{Content: "# Slide 2"}}},
+				},
+			},
+			new: &model.Presentation{
+				Slides: []model.Slide{
+					{Chunks: []model.Chunk{{Content: "# Slide 1"}}},
+					{Chunks: []model.Chunk{{Content: "# Slide 2"}}, Meta: model.SlideMeta{Class: "centered"}},
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "hiding a slide returns that slide",
+			old: &model.Presentation{
+				Slides: []model.Slide{
+					{Chunks: []model.Chunk{{Content: "# Slide 1"}}},
+					{Chunks: []model.Chunk{{Content: "# Slide 2"}}},
+				},
+			},
+			new: &model.Presentation{
+				Slides: []model.Slide{
+					{Chunks: []model.Chunk{{Content: "# Slide 1"}}},
+					{Chunks: []model.Chunk{{Content: "# Slide 2"}}, Meta: model.SlideMeta{Hidden: true}},
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "section change returns that slide",
+			old: &model.Presentation{
+				Slides: []model.Slide{
+					{Chunks: []model.Chunk{{Content: "# Slide 1"}}, Meta: model.SlideMeta{Section: "Intro"}},
+				},
+			},
+			new: &model.Presentation{
+				Slides: []model.Slide{
+					{Chunks: []model.Chunk{{Content: "# Slide 1"}}, Meta: model.SlideMeta{Section: "Overview"}},
+				},
+			},
+			want: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,161 @@
+// Package lint checks decks for problems and reports them with their
+// source positions.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/code"
+	"github.com/jedwards1230/deck/internal/model"
+	"github.com/jedwards1230/deck/internal/parse"
+)
+
+// Result holds the diagnostics for one linted file.
+type Result struct {
+	File        string
+	Diagnostics []model.Diagnostic
+}
+
+// Lint parses content read from opts.Path and returns the parser's
+// diagnostics together with deck-level checks, ordered by file and position.
+func Lint(content string, opts parse.Options) []model.Diagnostic {
+	path := opts.Path
+	pres := parse.Parse(content, opts)
+
+	diags := append([]model.Diagnostic(nil), pres.Diagnostics...)
+	diags = append(diags, checkEmptySlides(pres)...)
+	diags = append(diags, checkCodeLanguages(pres)...)
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Span, diags[j].Span
+		if a.File != b.File {
+			return a.File == path
+		}
+		return a.Start < b.Start
+	})
+	return diags
+}
+
+// Failed reports whether any result has an error or warning. Info
+// diagnostics never fail a lint run.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			if d.Severity <= model.SeverityWarning {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func checkEmptySlides(pres *model.Presentation) []model.Diagnostic {
+	var diags []model.Diagnostic
+	for i, slide := range pres.Slides {
+		if !isEmpty(slide) {
+			continue
+		}
+		diags = append(diags, model.Diagnostic{
+			Severity: model.SeverityWarning,
+			Code:     "empty-slide",
+			Message:  fmt.Sprintf("slide %d is empty", i+1),
+			Span:     slide.Span,
+		})
+	}
+	return diags
+}
+
+func isEmpty(slide model.Slide) bool {
+	for _, chunk := range slide.Chunks {
+		if strings.TrimSpace(chunk.Content) != "" {
+			return false
+		}
+	}
+	for _, col := range slide.Columns {
+		if strings.TrimSpace(col) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCodeLanguages flags fenced code blocks tagged with a language that
+// ctrl+e cannot execute. Untagged blocks are assumed to be display-only.
+func checkCodeLanguages(pres *model.Presentation) []model.Diagnostic {
+	var diags []model.Diagnostic
+	for _, slide := range pres.Slides {
+		for _, block := range slide.CodeBlocks {
+			if block.Language == "" {
+				continue
+			}
+			if _, ok := code.Languages[block.Language]; ok {
+				continue
+			}
+			span := block.Span
+			span.EndLine = span.StartLine // point at the opening fence
+			diags = append(diags, model.Diagnostic{
+				Severity: model.SeverityInfo,
+				Code:     "unrunnable-language",
+				Message:  fmt.Sprintf("%s code block cannot be executed", block.Language),
+				Span:     span,
+			})
+		}
+	}
+	return diags
+}
+
+// WriteText writes one "file:line: severity: message (code)" line per
+// diagnostic. Diagnostics in included files name that file.
+func WriteText(w io.Writer, results []Result) error {
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			if _, err := fmt.Fprintf(w, "%s:%d: %s: %s (%s)\n",
+				fileOf(r, d), d.Span.StartLine, d.Severity, d.Message, d.Code); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type jsonDiagnostic struct {
+	File     string         `json:"file"`
+	Line     int            `json:"line"`
+	EndLine  int            `json:"end_line"`
+	Severity model.Severity `json:"severity"`
+	Code     string         `json:"code"`
+	Message  string         `json:"message"`
+}
+
+// WriteJSON writes every diagnostic as a single JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	out := []jsonDiagnostic{}
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			out = append(out, jsonDiagnostic{
+				File:     fileOf(r, d),
+				Line:     d.Span.StartLine,
+				EndLine:  d.Span.EndLine,
+				Severity: d.Severity,
+				Code:     d.Code,
+				Message:  d.Message,
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// fileOf returns the file a diagnostic points into: the included file it
+// came from, or else the linted file.
+func fileOf(r Result, d model.Diagnostic) string {
+	if d.Span.File != "" {
+		return d.Span.File
+	}
+	return r.File
+}
@@ -0,0 +1,212 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jedwards1230/deck/internal/model"
+	"github.com/jedwards1230/deck/internal/parse"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantSev  model.Severity
+		wantLine int
+	}{
+		{
+			name:     "unknown command",
+			input:    "# One\n<!-- pasue -->\nText",
+			wantCode: "unknown-command",
+			wantSev:  model.SeverityWarning,
+			wantLine: 2,
+		},
+		{
+			name:     "invalid arguments to a known command",
+			input:    "# One\n\n<!-- column_layout: [0, 1] -->",
+			wantCode: "invalid-command",
+			wantSev:  model.SeverityWarning,
+			wantLine: 3,
+		},
+		{
+			name:     "column index outside layout",
+			input:    "<!-- column_layout: [1, 1] -->\n<!-- column: 0 -->\nA\n<!-- column: 2 -->\nB",
+			wantCode: "column-out-of-range",
+			wantSev:  model.SeverityError,
+			wantLine: 4,
+		},
+		{
+			name:     "column marker without layout",
+			input:    "# One\n\n<!-- column: 0 -->\nA",
+			wantCode: "column-without-layout",
+			wantSev:  model.SeverityWarning,
+			wantLine: 3,
+		},
+		{
+			name:     "unclosed frontmatter",
+			input:    "---\nauthor: Me\n# Slide",
+			wantCode: "unclosed-frontmatter",
+			wantSev:  model.SeverityError,
+			wantLine: 1,
+		},
+		{
+			name:     "yaml syntax error maps to source line",
+			input:    "---\nauthor: Me\npaging: : oops\n---\n# Slide",
+			wantCode: "frontmatter-yaml",
+			wantSev:  model.SeverityError,
+			wantLine: 3,
+		},
+		{
+			name:     "yaml type error maps to source line",
+			input:    "---\nauthor: Me\n\ndate:\n  - a\n---\n# Slide",
+			wantCode: "frontmatter-yaml",
+			wantSev:  model.SeverityError,
+			wantLine: 5,
+		},
+		{
+			name:     "empty slide",
+			input:    "# One\n---\n<!-- speaker_note: nothing to show -->\n---\n# Three",
+			wantCode: "empty-slide",
+			wantSev:  model.SeverityWarning,
+			wantLine: 3,
+		},
+		{
+			name:     "code block in a language that cannot run",
+			input:    "# One\n\n```yaml\na: 1\n```",
+			wantCode: "unrunnable-language",
+			wantSev:  model.SeverityInfo,
+			wantLine: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := Lint(tt.input, parse.Options{})
+			if len(diags) != 1 {
+				t.Fatalf("got %d diagnostics, want 1: %+v", len(diags), diags)
+			}
+			d := diags[0]
+			if d.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", d.Code, tt.wantCode)
+			}
+			if d.Severity != tt.wantSev {
+				t.Errorf("severity = %v, want %v", d.Severity, tt.wantSev)
+			}
+			if d.Span.StartLine != tt.wantLine {
+				t.Errorf("line = %d, want %d", d.Span.StartLine, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestLintCleanDeck(t *testing.T) {
+	input := `---
+author: Clean
+---
+# Title
+
+<!-- TODO: polish wording -->
+<!-- pause -->
+
+` + "```bash\necho hi\n```\n\n```\nuntagged\n```" + `
+---
+<!-- column_layout: [1, 1] -->
+<!-- column: 0 -->
+Left
+<!-- column: 1 -->
+Right
+<!-- speaker_note: Fine -->`
+
+	if diags := Lint(input, parse.Options{}); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diags)
+	}
+}
+
+func TestLintIgnoresCommentsInCode(t *testing.T) {
+	input := "# HTML\n\n```html\n<!-- not_a_command -->\n```"
+	for _, d := range Lint(input, parse.Options{}) {
+		if d.Code == "unknown-command" {
+			t.Errorf("comment inside a code block was reported: %+v", d)
+		}
+	}
+}
+
+func TestFailed(t *testing.T) {
+	info := []Result{{File: "a.md", Diagnostics: []model.Diagnostic{{Severity: model.SeverityInfo}}}}
+	if Failed(info) {
+		t.Error("info diagnostics should not fail")
+	}
+	warn := []Result{{File: "a.md", Diagnostics: []model.Diagnostic{{Severity: model.SeverityWarning}}}}
+	if !Failed(warn) {
+		t.Error("warning diagnostics should fail")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	results := []Result{{File: "talk.md", Diagnostics: Lint("# One\n<!-- pasue -->", parse.Options{})}}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, results); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	want := "talk.md:2: warning: unknown command \"pasue\" (unknown-command)\n"
+	if buf.String() != want {
+		t.Errorf("WriteText() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteTextIncludedFile(t *testing.T) {
+	dir := t.TempDir()
+	part := filepath.Join(dir, "part.md")
+	if err := os.WriteFile(part, []byte("# Part\n\n<!-- pasue -->\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deck := filepath.Join(dir, "deck.md")
+	results := []Result{{File: deck, Diagnostics: Lint("# One\n---\n<!-- include: part.md -->\n", parse.Options{Path: deck})}}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, results); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	want := part + ":3: warning: unknown command \"pasue\" (unknown-command)\n"
+	if buf.String() != want {
+		t.Errorf("WriteText() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	results := []Result{
+		{File: "a.md", Diagnostics: Lint("# One\n<!-- pasue -->", parse.Options{})},
+		{File: "b.md", Diagnostics: nil},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, results); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1", len(got))
+	}
+	if got[0]["file"] != "a.md" || got[0]["severity"] != "warning" || got[0]["line"] != float64(2) {
+		t.Errorf("unexpected entry %v", got[0])
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty results should encode as [], got %q", buf.String())
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+// Horizontal alignments of a slide or grid cell. Empty means left.
+const (
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
+)
+
+// Vertical alignments of a slide or grid cell. Empty means top.
+const (
+	VAlignTop    = "top"
+	VAlignMiddle = "middle"
+	VAlignBottom = "bottom"
+)
+
+// ValidAlign reports whether s is a horizontal alignment.
+func ValidAlign(s string) bool {
+	return s == AlignLeft || s == AlignCenter || s == AlignRight
+}
+
+// ValidVAlign reports whether s is a vertical alignment.
+func ValidVAlign(s string) bool {
+	return s == VAlignTop || s == VAlignMiddle || s == VAlignBottom
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Link is an internal link, [text](#target), on a slide.
+type Link struct {
+	Text   string
+	Target string // anchor name, without the #
+	Span   Span
+}
+
+// Slug turns heading text into an anchor name the way GitHub does:
+// lowercased, with spaces as hyphens and other punctuation dropped.
+func Slug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// AnchorIndex returns the index of the slide a #name link points to: the
+// slide whose id is name or, failing that, the first slide with a heading
+// anchor name. It returns -1 when no slide matches.
+func AnchorIndex(slides []Slide, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, slide := range slides {
+		if slide.Meta.ID == name {
+			return i
+		}
+	}
+	for i, slide := range slides {
+		for _, anchor := range slide.Anchors {
+			if anchor == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,43 @@
+package model
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Live Demo", "live-demo"},
+		{"  What's *new* in v2.0?  ", "whats-new-in-v20"},
+		{"snake_case and-hyphens", "snake_case-and-hyphens"},
+		{"Café `code`", "café-code"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.input); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAnchorIndex(t *testing.T) {
+	slides := []Slide{
+		{Anchors: []string{"intro"}},
+		{Anchors: []string{"demo"}},
+		{Meta: SlideMeta{ID: "demo"}},
+	}
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"intro", 0},
+		{"demo", 2}, // an explicit id wins over a heading anchor
+		{"missing", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := AnchorIndex(slides, tt.name); got != tt.want {
+			t.Errorf("AnchorIndex(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,4 +3,9 @@ package model
 // Chunk is a unit of progressive reveal (content between pause commands).
 type Chunk struct {
 	Content string // raw markdown (commands stripped)
+	Span    Span   // source region the chunk was cut from
+
+	// Cells holds, on a slide with a grid, the content this chunk adds to
+	// each cell, parallel to Slide.Columns.
+	Cells []string
 }
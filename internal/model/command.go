@@ -9,12 +9,28 @@ const (
 	CmdColumnLayout
 	CmdColumn
 	CmdResetLayout
+	CmdInclude
+	CmdSlideMeta
+	CmdRow
+	CmdGrid
+	CmdEndGrid
+	CmdAlign
+	CmdVAlign
+	CmdSkip
+	CmdTOC
+	CmdSection
+	CmdOnly
+	CmdEndOnly
+	CmdLang
+	CmdEndLang
+	CmdFile
+	CmdTable
 )
 
 // Command represents a parsed HTML comment command.
 type Command struct {
 	Type   CommandType
-	Value  string // for speaker notes: the note text
-	Ratios []int  // for column_layout: proportional widths
+	Value  string // for speaker notes: the note text; for include: the path; for slide: the YAML; for align and valign: the alignment; for toc: the options; for section: the name; for only: the profiles; for lang: the language; for file: the path and selector; for table: the path and options
+	Ratios []int  // for column_layout and row: proportional widths
 	Column int    // for column: the column index (0-based)
 }
@@ -0,0 +1,38 @@
+package model
+
+import "fmt"
+
+// Severity ranks how serious a Diagnostic is.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the severity by name, e.g. for JSON output.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found while parsing or checking a deck.
+type Diagnostic struct {
+	Severity Severity
+	Code     string // stable identifier, e.g. "unknown-command"
+	Message  string
+	Span     Span
+}
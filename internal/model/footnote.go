@@ -0,0 +1,46 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// superscripts are the superscript forms of the digits 0-9.
+var superscripts = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// Footnote is a markdown footnote, [^label]: text, collected from a slide.
+// References to it on the slide show its number as a superscript.
+type Footnote struct {
+	Label  string // as written, without [^ ]
+	Number int    // 1-based, in order of first reference on the slide
+	Text   string // the definition, joined onto one line
+	Chunk  int    // the chunk that first references it
+	Span   Span   // the definition
+}
+
+// Marker returns the footnote's number in superscript digits.
+func (f Footnote) Marker() string {
+	return Superscript(f.Number)
+}
+
+// Superscript writes n, which must not be negative, in superscript
+// digits.
+func Superscript(n int) string {
+	var b strings.Builder
+	for _, r := range strconv.Itoa(n) {
+		b.WriteRune(superscripts[r-'0'])
+	}
+	return b.String()
+}
+
+// VisibleFootnotes returns the footnotes referenced by chunks
+// 0..chunkIndex, so a citation appears with the text that cites it.
+func (s Slide) VisibleFootnotes(chunkIndex int) []Footnote {
+	var visible []Footnote
+	for _, f := range s.Footnotes {
+		if f.Chunk <= chunkIndex {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
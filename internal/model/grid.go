@@ -0,0 +1,28 @@
+package model
+
+// Grid is a slide layout of rows stacked top to bottom, each divided into
+// cells by proportional widths. A cell holds content or a nested grid.
+type Grid struct {
+	Rows []GridRow
+}
+
+// GridRow is one row of a Grid. Cells is parallel to Ratios.
+type GridRow struct {
+	Ratios []int
+	Cells  []GridCell
+}
+
+// GridCell is one cell of a GridRow.
+type GridCell struct {
+	Leaf int   // index into Slide.Columns; unused when Grid is set
+	Grid *Grid // nested grid, or nil for a content cell
+
+	Align  string // horizontal alignment within the cell's width
+	VAlign string // vertical alignment within the row's height
+}
+
+// Widths resolves the row's ratios to character widths, as ColumnLayout
+// does.
+func (r GridRow) Widths(totalWidth int) []int {
+	return ColumnLayout{Ratios: r.Ratios}.ColumnWidths(totalWidth)
+}
@@ -5,6 +5,16 @@ type ColumnLayout struct {
 	Ratios []int // e.g., [3, 2] means 3/5 and 2/5
 }
 
+// Grid returns the layout as a one-row grid whose cells are columns 0
+// to n-1.
+func (l ColumnLayout) Grid() Grid {
+	row := GridRow{Ratios: l.Ratios, Cells: make([]GridCell, len(l.Ratios))}
+	for i := range row.Cells {
+		row.Cells[i].Leaf = i
+	}
+	return Grid{Rows: []GridRow{row}}
+}
+
 // ColumnWidths resolves ratios to actual character widths for the given total width.
 // It accounts for column gaps (2 chars between columns).
 func (l ColumnLayout) ColumnWidths(totalWidth int) []int {
@@ -73,3 +73,13 @@ func TestColumnWidths(t *testing.T) {
 		})
 	}
 }
+
+func TestColumnLayoutGrid(t *testing.T) {
+	g := ColumnLayout{Ratios: []int{2, 1}}.Grid()
+	if len(g.Rows) != 1 || len(g.Rows[0].Cells) != 2 {
+		t.Fatalf("Grid() = %+v, want one row of two cells", g)
+	}
+	if cells := g.Rows[0].Cells; cells[0].Leaf != 0 || cells[1].Leaf != 1 {
+		t.Errorf("Grid() cells = %+v, want columns 0 and 1", cells)
+	}
+}
@@ -4,6 +4,10 @@ package model
 type Presentation struct {
 	Slides      []Slide
 	Frontmatter Frontmatter
+	Diagnostics []Diagnostic // problems found while parsing, in source order
+	Sources     []string     // every file read while parsing, the deck itself first
+	Missing     []string     // files the deck names that could not be read
+	Languages   []string     // languages of its lang regions, the frontmatter's lang first
 }
 
 // Frontmatter holds YAML metadata from the slide file header.
@@ -12,4 +16,38 @@ type Frontmatter struct {
 	Date   string `yaml:"date"`
 	Paging string `yaml:"paging"`
 	Footer string `yaml:"footer"`
+
+	// Slides lists files whose slides follow the deck's own, in order.
+	Slides []string `yaml:"slides"`
+
+	// Separator is a regular expression matching whole lines that separate
+	// slides, replacing ---.
+	Separator string `yaml:"separator"`
+
+	// SlideLevel makes every heading of this level or higher (1-6) start a
+	// new slide. 0 splits only at separators.
+	SlideLevel int `yaml:"slide_level"`
+
+	// IncrementalLists reveals the top-level items of lists one at a time.
+	IncrementalLists bool `yaml:"incremental_lists"`
+
+	// Align and VAlign are the default placement of every slide on screen.
+	Align  string `yaml:"align"`
+	VAlign string `yaml:"valign"`
+
+	// Vars are values slides reference as {{ .name }}.
+	Vars map[string]string `yaml:"vars"`
+
+	// Profile selects the audience the deck is presented for, dropping
+	// slides and only regions meant for other profiles. Empty keeps all.
+	Profile string `yaml:"profile"`
+
+	// Lang selects the language of lang regions to present. Empty picks
+	// the first language the deck's regions use.
+	Lang string `yaml:"lang"`
+
+	// Extra holds the keys deck does not know, for templates and plugins
+	// to read. Footer templates show a scalar value as {key}; {{ .key }}
+	// expansion reads only Vars.
+	Extra map[string]any `yaml:",inline"`
 }
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles names the audiences some content is meant for, such as
+// "internal" or "external". In YAML it is one name or a list of names.
+type Profiles []string
+
+// UnmarshalYAML accepts a single name as well as a list.
+func (p *Profiles) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*p = Profiles{value.Value}
+		return nil
+	}
+	var names []string
+	if err := value.Decode(&names); err != nil {
+		return err
+	}
+	*p = names
+	return nil
+}
+
+// Allows reports whether content limited to p is shown when presenting
+// for profile. Content without profiles is always shown, and so is
+// everything when no profile is selected.
+func (p Profiles) Allows(profile string) bool {
+	return profile == "" || len(p) == 0 || slices.Contains(p, profile)
+}
@@ -0,0 +1,14 @@
+package model
+
+// SectionName returns the name of the section slide i is in: the section
+// started by the nearest slide at or before it with a <!-- section: ... -->
+// comment or section option. Slides before the first section are in none
+// and get "".
+func SectionName(slides []Slide, i int) string {
+	for i = min(i, len(slides)-1); i >= 0; i-- {
+		if slides[i].Meta.Section != "" {
+			return slides[i].Meta.Section
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package model
+
+import "testing"
+
+func TestSectionName(t *testing.T) {
+	slides := []Slide{
+		{},
+		{Meta: SlideMeta{Section: "Architecture"}},
+		{},
+		{Meta: SlideMeta{Section: "Rollout"}},
+	}
+	want := []string{"", "Architecture", "Architecture", "Rollout"}
+	for i, w := range want {
+		if got := SectionName(slides, i); got != w {
+			t.Errorf("SectionName(%d) = %q, want %q", i, got, w)
+		}
+	}
+	if got := SectionName(nil, 0); got != "" {
+		t.Errorf("SectionName(nil, 0) = %q, want empty", got)
+	}
+}
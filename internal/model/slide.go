@@ -1,11 +1,139 @@
 package model
 
+import (
+	"regexp"
+	"strings"
+)
+
+// headingRegex matches an ATX heading line, capturing its text.
+var headingRegex = regexp.MustCompile(`^ {0,3}#{1,6}[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
 // Slide represents a single slide in the presentation.
 type Slide struct {
 	Chunks       []Chunk
-	Columns      []string       // per-column content (populated when Layout is set)
+	Columns      []string // per-cell content of Grid, in reading order
 	SpeakerNotes []string
-	Layout       *ColumnLayout // nil = full-width
+	Grid         *Grid // nil = full-width
+	Meta         SlideMeta
+	Align        string     // horizontal placement on screen; see AlignLeft
+	VAlign       string     // vertical placement on screen; see VAlignTop
+	Anchors      []string   // slugs of the slide's headings, unique in the deck
+	Links        []Link     // internal links, in source order
+	Footnotes    []Footnote // by number
+
+	Span        Span   // the whole slide, excluding its --- delimiters
+	ColumnSpans []Span // parallel to Columns
+	NoteSpans   []Span // parallel to SpeakerNotes
+	CodeBlocks  []CodeBlock
+}
+
+// VisibleColumns returns the content of each grid cell revealed through
+// chunk chunkIndex. Slides whose chunks carry no cell content show every
+// cell in full.
+func (s Slide) VisibleColumns(chunkIndex int) []string {
+	if chunkIndex >= len(s.Chunks) {
+		chunkIndex = len(s.Chunks) - 1
+	}
+	revealed := false
+	columns := make([]string, len(s.Columns))
+	for i := 0; i <= chunkIndex; i++ {
+		for leaf, content := range s.Chunks[i].Cells {
+			if leaf < len(columns) {
+				columns[leaf] += content
+				revealed = true
+			}
+		}
+	}
+	if !revealed {
+		return s.Columns
+	}
+	return columns
+}
+
+// SlideMeta holds per-slide options set by a <!-- slide: {...} -->
+// comment.
+type SlideMeta struct {
+	ID     string `yaml:"id"`     // target for #id search
+	Title  string `yaml:"title"`  // overrides the first heading
+	Footer *bool  `yaml:"footer"` // false hides the footer; nil shows it
+	Class  string `yaml:"class"`  // "centered" centers the slide
+	Hidden bool   `yaml:"hidden"` // skipped by normal navigation and paging
+
+	// Section names the section this slide starts; see SectionName.
+	Section string `yaml:"section"`
+
+	// Only limits the slide to these audience profiles; see
+	// Profiles.Allows.
+	Only Profiles `yaml:"only"`
+
+	// IncrementalLists overrides the frontmatter setting for this slide;
+	// nil inherits it.
+	IncrementalLists *bool `yaml:"incremental_lists"`
+}
+
+// ShowFooter reports whether the footer is drawn under the slide.
+func (m SlideMeta) ShowFooter() bool {
+	return m.Footer == nil || *m.Footer
+}
+
+// Title returns the slide's explicit title, or else the text of its first
+// heading outside code blocks. It returns "" for a slide without either.
+func (s Slide) Title() string {
+	if s.Meta.Title != "" {
+		return s.Meta.Title
+	}
+	text, _ := s.firstHeading()
+	return text
+}
+
+// TitleLevel returns the level of the slide's first heading outside code
+// blocks, 1 for #. A slide with an explicit title but no heading is level 1,
+// and a slide without a title is level 0.
+func (s Slide) TitleLevel() int {
+	_, level := s.firstHeading()
+	if level == 0 && s.Meta.Title != "" {
+		return 1
+	}
+	return level
+}
+
+// firstHeading returns the text and level of the slide's first heading
+// outside code blocks.
+func (s Slide) firstHeading() (string, int) {
+	fence := ""
+	for _, line := range strings.Split(s.VisibleContent(len(s.Chunks)-1), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" \t") == "" {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if text, ok := HeadingText(line); ok {
+			return text, strings.Count(strings.Fields(line)[0], "#")
+		}
+	}
+	return "", 0
+}
+
+// HeadingText returns the text of an ATX heading line, without its
+// markers.
+func HeadingText(line string) (string, bool) {
+	m := headingRegex.FindStringSubmatch(line)
+	if m == nil || m[1] == "" {
+		return "", false
+	}
+	return m[1], true
+}
+
+// CodeBlock locates a fenced code block in the source.
+type CodeBlock struct {
+	Language string // first word of the info string; empty if untagged
+	Span     Span   // the block including its fences
 }
 
 // VisibleContent returns the concatenated content of chunks 0..chunkIndex.
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -84,3 +85,114 @@ func TestVisibleContent(t *testing.T) {
 		})
 	}
 }
+
+func TestSlideTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		slide Slide
+		want  string
+	}{
+		{
+			name:  "first heading",
+			slide: Slide{Chunks: []Chunk{{Content: "Intro text\n\n## Why deck ##\n\n# Later"}}},
+			want:  "Why deck",
+		},
+		{
+			name:  "heading in a later chunk",
+			slide: Slide{Chunks: []Chunk{{Content: "No heading\n"}, {Content: "# Revealed"}}},
+			want:  "Revealed",
+		},
+		{
+			name:  "skips headings in code",
+			slide: Slide{Chunks: []Chunk{{Content: "```md\n# Not a title\n```\n# Title"}}},
+			want:  "Title",
+		},
+		{
+			name: "explicit title wins",
+			slide: Slide{
+				Chunks: []Chunk{{Content: "# Heading"}},
+				Meta:   SlideMeta{Title: "Explicit"},
+			},
+			want: "Explicit",
+		},
+		{
+			name:  "no title",
+			slide: Slide{Chunks: []Chunk{{Content: "#hashtag is not a heading"}}},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.slide.Title(); got != tt.want {
+				t.Errorf("Title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlideTitleLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		slide Slide
+		want  int
+	}{
+		{"h2", Slide{Chunks: []Chunk{{Content: "Intro\n\n## Why deck ##"}}}, 2},
+		{"code skipped", Slide{Chunks: []Chunk{{Content: "```\n# no\n```\n### Three"}}}, 3},
+		{"explicit title keeps heading level", Slide{Chunks: []Chunk{{Content: "## Two"}}, Meta: SlideMeta{Title: "T"}}, 2},
+		{"explicit title only", Slide{Chunks: []Chunk{{Content: "text"}}, Meta: SlideMeta{Title: "T"}}, 1},
+		{"no title", Slide{Chunks: []Chunk{{Content: "text"}}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.slide.TitleLevel(); got != tt.want {
+				t.Errorf("TitleLevel() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlideVisibleColumns(t *testing.T) {
+	slide := Slide{
+		Columns: []string{"A1A2", "B1"},
+		Chunks: []Chunk{
+			{Cells: []string{"A1", ""}},
+			{Cells: []string{"", "B1"}},
+			{Cells: []string{"A2", ""}},
+		},
+	}
+	tests := []struct {
+		chunk int
+		want  []string
+	}{
+		{0, []string{"A1", ""}},
+		{1, []string{"A1", "B1"}},
+		{2, []string{"A1A2", "B1"}},
+		{5, []string{"A1A2", "B1"}},
+	}
+	for _, tt := range tests {
+		got := slide.VisibleColumns(tt.chunk)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("VisibleColumns(%d) = %q, want %q", tt.chunk, got, tt.want)
+		}
+	}
+
+	noSteps := Slide{Columns: []string{"A", "B"}, Chunks: []Chunk{{Content: "A B"}}}
+	if got := noSteps.VisibleColumns(0); strings.Join(got, "|") != "A|B" {
+		t.Errorf("VisibleColumns without cell steps = %q, want every column", got)
+	}
+}
+
+func TestSlideMetaShowFooter(t *testing.T) {
+	off, on := false, true
+	if !(SlideMeta{}).ShowFooter() {
+		t.Error("footer hidden by default")
+	}
+	if (SlideMeta{Footer: &off}).ShowFooter() {
+		t.Error("footer: false should hide the footer")
+	}
+	if !(SlideMeta{Footer: &on}).ShowFooter() {
+		t.Error("footer: true should show the footer")
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+// Span locates a region of the deck source. Lines are 1-based and
+// inclusive; offsets are 0-based byte offsets into File with End
+// exclusive, measured after line endings are normalized to \n.
+type Span struct {
+	File      string // empty for content read from stdin
+	StartLine int
+	EndLine   int
+	Start     int
+	End       int
+}
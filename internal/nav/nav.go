@@ -18,6 +18,8 @@ func Navigate(state State, keyPress string) State {
 			SlideIndex:    state.SlideIndex,
 			ChunkIndex:    state.ChunkIndex,
 			TotalSlides:   state.TotalSlides,
+			Hidden:        state.Hidden,
+			Sections:      state.Sections,
 			ChunksInSlide: state.ChunksInSlide,
 			Buffer:        newBuffer,
 		}
@@ -25,9 +27,11 @@ func Navigate(state State, keyPress string) State {
 	case "g":
 		if state.Buffer == "g" {
 			return State{
-				SlideIndex:    0,
+				SlideIndex:    state.FirstVisible(),
 				ChunkIndex:    0,
 				TotalSlides:   state.TotalSlides,
+				Hidden:        state.Hidden,
+				Sections:      state.Sections,
 				ChunksInSlide: state.ChunksInSlide,
 			}
 		}
@@ -35,24 +39,58 @@ func Navigate(state State, keyPress string) State {
 			SlideIndex:    state.SlideIndex,
 			ChunkIndex:    state.ChunkIndex,
 			TotalSlides:   state.TotalSlides,
+			Hidden:        state.Hidden,
+			Sections:      state.Sections,
 			ChunksInSlide: state.ChunksInSlide,
 			Buffer:        "g",
 		}
 
+	case "]", "[":
+		if state.Buffer == keyPress {
+			target := state.nextSection()
+			if keyPress == "[" {
+				target = state.prevSection()
+			}
+			if target < 0 {
+				target = state.SlideIndex
+			}
+			return State{
+				SlideIndex:    target,
+				ChunkIndex:    0,
+				TotalSlides:   state.TotalSlides,
+				Hidden:        state.Hidden,
+				Sections:      state.Sections,
+				ChunksInSlide: state.ChunksInSlide,
+			}
+		}
+		return State{
+			SlideIndex:    state.SlideIndex,
+			ChunkIndex:    state.ChunkIndex,
+			TotalSlides:   state.TotalSlides,
+			Hidden:        state.Hidden,
+			Sections:      state.Sections,
+			ChunksInSlide: state.ChunksInSlide,
+			Buffer:        keyPress,
+		}
+
 	case "G":
 		if bufferIsNumeric(state.Buffer) {
-			target := navigateToSlide(state.Buffer, state.TotalSlides)
+			target := state.pageSlide(navigateToSlide(state.Buffer, state.TotalSlides))
 			return State{
 				SlideIndex:    target,
 				ChunkIndex:    0,
 				TotalSlides:   state.TotalSlides,
+				Hidden:        state.Hidden,
+				Sections:      state.Sections,
 				ChunksInSlide: state.ChunksInSlide,
 			}
 		}
 		return State{
-			SlideIndex:    state.TotalSlides - 1,
+			SlideIndex:    state.lastVisible(),
 			ChunkIndex:    0,
 			TotalSlides:   state.TotalSlides,
+			Hidden:        state.Hidden,
+			Sections:      state.Sections,
 			ChunksInSlide: state.ChunksInSlide,
 		}
 
@@ -68,6 +106,8 @@ func Navigate(state State, keyPress string) State {
 			SlideIndex:    state.SlideIndex,
 			ChunkIndex:    state.ChunkIndex,
 			TotalSlides:   state.TotalSlides,
+			Hidden:        state.Hidden,
+			Sections:      state.Sections,
 			ChunksInSlide: state.ChunksInSlide,
 		}
 	}
@@ -89,8 +129,8 @@ func advanceForward(state State) State {
 	for range repeat {
 		if chunk < chunksInSlide-1 {
 			chunk++
-		} else if slide < state.TotalSlides-1 {
-			slide++
+		} else if next := state.nextVisible(slide); next >= 0 {
+			slide = next
 			chunk = 0
 			chunksInSlide = 1 // will be corrected by caller
 		}
@@ -100,6 +140,8 @@ func advanceForward(state State) State {
 		SlideIndex:    slide,
 		ChunkIndex:    chunk,
 		TotalSlides:   state.TotalSlides,
+		Hidden:        state.Hidden,
+		Sections:      state.Sections,
 		ChunksInSlide: chunksInSlide,
 	}
 }
@@ -119,8 +161,8 @@ func advanceBackward(state State) State {
 	for range repeat {
 		if chunk > 0 {
 			chunk--
-		} else if slide > 0 {
-			slide--
+		} else if prev := state.prevVisible(slide); prev >= 0 {
+			slide = prev
 			chunk = math.MaxInt // sentinel: show all chunks of previous slide (caller clamps)
 		}
 	}
@@ -129,6 +171,8 @@ func advanceBackward(state State) State {
 		SlideIndex:    slide,
 		ChunkIndex:    chunk,
 		TotalSlides:   state.TotalSlides,
+		Hidden:        state.Hidden,
+		Sections:      state.Sections,
 		ChunksInSlide: state.ChunksInSlide,
 	}
 }
@@ -648,3 +648,140 @@ func TestNavigateToSlide(t *testing.T) {
 		})
 	}
 }
+
+func TestNavigateHidden(t *testing.T) {
+	// Slides 0 and 3 are hidden; 4 is a backup slide after the last visible one.
+	hidden := []bool{true, false, false, true, true}
+	base := State{TotalSlides: 5, ChunksInSlide: 1, Hidden: &hidden}
+
+	at := func(slide int, buffer string) State {
+		s := base
+		s.SlideIndex = slide
+		s.Buffer = buffer
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		state     State
+		key       string
+		wantSlide int
+	}{
+		{"forward skips hidden", at(2, ""), "l", 2},
+		{"forward over hidden to nothing stays", at(2, ""), "space", 2},
+		{"backward skips hidden", at(4, ""), "h", 2},
+		{"backward from first visible stays", at(1, ""), "h", 1},
+		{"forward from hidden slide", at(0, ""), "l", 1},
+		{"gg goes to first visible", at(2, "g"), "g", 1},
+		{"G goes to last visible", at(1, ""), "G", 2},
+		{"numbered jump counts pages", at(1, "2"), "G", 2},
+		{"numbered jump to first page", at(2, "1"), "G", 1},
+		{"numbered jump past last page", at(1, "4"), "G", 2},
+		{"count skips hidden", at(1, "2"), "j", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Navigate(tt.state, tt.key)
+			if got.SlideIndex != tt.wantSlide {
+				t.Errorf("Navigate(%d, %q) slide = %d, want %d", tt.state.SlideIndex, tt.key, got.SlideIndex, tt.wantSlide)
+			}
+			if got.Hidden != tt.state.Hidden {
+				t.Error("Navigate dropped the hidden slides")
+			}
+		})
+	}
+}
+
+func TestStatePage(t *testing.T) {
+	hidden := []bool{true, false, false, true, true}
+	tests := []struct {
+		slide       int
+		hidden      *[]bool
+		wantCurrent int
+		wantTotal   int
+	}{
+		{1, &hidden, 0, 2},
+		{2, &hidden, 1, 2},
+		{4, &hidden, 1, 2}, // a backup slide shares the last visible page
+		{0, &hidden, 0, 2},
+		{3, nil, 3, 5},
+	}
+	for _, tt := range tests {
+		s := State{SlideIndex: tt.slide, TotalSlides: 5, Hidden: tt.hidden}
+		if current, total := s.Page(); current != tt.wantCurrent || total != tt.wantTotal {
+			t.Errorf("slide %d: Page() = %d, %d; want %d, %d", tt.slide, current, total, tt.wantCurrent, tt.wantTotal)
+		}
+	}
+}
+
+func TestNavigateSections(t *testing.T) {
+	// Sections start at slides 1, 3 and 6; slide 0 comes before the first
+	// and slide 3 is hidden.
+	hidden := []bool{false, false, false, true, false, false, false, false}
+	sections := []bool{false, true, false, true, false, false, true, false}
+	base := State{TotalSlides: 8, ChunksInSlide: 1, Hidden: &hidden, Sections: &sections}
+
+	at := func(slide int, buffer string) State {
+		s := base
+		s.SlideIndex = slide
+		s.Buffer = buffer
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		state      State
+		key        string
+		wantSlide  int
+		wantBuffer string
+	}{
+		{"] waits for a second ]", at(0, ""), "]", 0, "]"},
+		{"]] from before the first section", at(0, "]"), "]", 1, ""},
+		{"]] lands after a hidden section start", at(1, "]"), "]", 4, ""},
+		{"]] to the last section", at(4, "]"), "]", 6, ""},
+		{"]] in the last section stays", at(7, "]"), "]", 7, ""},
+		{"[[ to the current section's start", at(5, "["), "[", 4, ""},
+		{"[[ at a section start goes to the previous one", at(4, "["), "[", 1, ""},
+		{"[[ from the first section to the slides before it", at(1, "["), "[", 0, ""},
+		{"[[ on the first slide stays", at(0, "["), "[", 0, ""},
+		{"mixed brackets only buffer", at(2, "]"), "[", 2, "["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Navigate(tt.state, tt.key)
+			if got.SlideIndex != tt.wantSlide || got.Buffer != tt.wantBuffer {
+				t.Errorf("Navigate(%d, %q) = slide %d buffer %q, want slide %d buffer %q",
+					tt.state.SlideIndex, tt.key, got.SlideIndex, got.Buffer, tt.wantSlide, tt.wantBuffer)
+			}
+			if got.Sections != tt.state.Sections {
+				t.Error("Navigate dropped the sections")
+			}
+		})
+	}
+}
+
+func TestStateSectionPage(t *testing.T) {
+	hidden := []bool{false, false, false, true, false, false, false, false}
+	sections := []bool{false, true, false, true, false, false, true, false}
+	tests := []struct {
+		slide       int
+		sections    *[]bool
+		wantCurrent int
+		wantTotal   int
+	}{
+		{0, &sections, 0, 1}, // the slides before the first section
+		{2, &sections, 1, 2},
+		{3, &sections, 0, 2}, // a hidden section start shares the first page
+		{5, &sections, 1, 2},
+		{7, &sections, 1, 2},
+		{5, nil, 4, 7}, // no sections: the whole deck
+	}
+	for _, tt := range tests {
+		s := State{SlideIndex: tt.slide, TotalSlides: 8, Hidden: &hidden, Sections: tt.sections}
+		if current, total := s.SectionPage(); current != tt.wantCurrent || total != tt.wantTotal {
+			t.Errorf("slide %d: SectionPage() = %d, %d; want %d, %d", tt.slide, current, total, tt.wantCurrent, tt.wantTotal)
+		}
+	}
+}
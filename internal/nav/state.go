@@ -7,4 +7,155 @@ type State struct {
 	TotalSlides   int
 	ChunksInSlide int    // number of chunks in current slide
 	Buffer        string // numeric prefix buffer for vim-style navigation
+
+	// Hidden marks the slides that moving forward and back, gg and G pass
+	// over; numbered jumps count pages, so they skip them too. It is a
+	// pointer so State stays comparable, and nil hides nothing.
+	Hidden *[]bool
+
+	// Sections marks the slides that start a section, for ]] and [[ and
+	// SectionPage. Like Hidden it is a pointer, and nil means no sections.
+	Sections *[]bool
+}
+
+// IsHidden reports whether slide i is hidden.
+func (s State) IsHidden(i int) bool {
+	return s.Hidden != nil && i >= 0 && i < len(*s.Hidden) && (*s.Hidden)[i]
+}
+
+// Page returns the 0-based page number of the current slide and the page
+// count, counting only slides that are not hidden. A hidden slide shares
+// the page of the visible slide before it. When every slide is hidden, all
+// of them are counted.
+func (s State) Page() (current, total int) {
+	current = -1
+	for i := range s.TotalSlides {
+		if s.IsHidden(i) {
+			continue
+		}
+		if i <= s.SlideIndex {
+			current++
+		}
+		total++
+	}
+	if total == 0 {
+		return s.SlideIndex, s.TotalSlides
+	}
+	return max(current, 0), total
+}
+
+// pageSlide returns the slide shown as 0-based page n, counting pages as
+// Page does. n past the last page gives the last visible slide.
+func (s State) pageSlide(n int) int {
+	last := -1
+	for i := range s.TotalSlides {
+		if s.IsHidden(i) {
+			continue
+		}
+		if n <= 0 {
+			return i
+		}
+		n--
+		last = i
+	}
+	if last < 0 {
+		return min(n, s.TotalSlides-1)
+	}
+	return last
+}
+
+// startsSection reports whether slide i starts a section.
+func (s State) startsSection(i int) bool {
+	return s.Sections != nil && i >= 0 && i < len(*s.Sections) && (*s.Sections)[i]
+}
+
+// SectionPage returns the 0-based page of the current slide within its
+// section and the section's page count, counting pages as Page does.
+// Slides before the first section count as a section of their own.
+func (s State) SectionPage() (current, total int) {
+	start := s.SlideIndex
+	for start > 0 && !s.startsSection(start) {
+		start--
+	}
+	end := s.SlideIndex + 1
+	for end < s.TotalSlides && !s.startsSection(end) {
+		end++
+	}
+
+	current = -1
+	for i := start; i < end; i++ {
+		if s.IsHidden(i) {
+			continue
+		}
+		if i <= s.SlideIndex {
+			current++
+		}
+		total++
+	}
+	if total == 0 {
+		return s.SlideIndex - start, end - start
+	}
+	return max(current, 0), total
+}
+
+// nextSection returns the first visible slide of the next section after
+// the current slide, or -1.
+func (s State) nextSection() int {
+	for i := s.SlideIndex + 1; i < s.TotalSlides; i++ {
+		if s.startsSection(i) {
+			if target := s.nextVisible(i - 1); target > s.SlideIndex {
+				return target
+			}
+		}
+	}
+	return -1
+}
+
+// prevSection returns the first visible slide of the current section, or
+// of the one before when already there, or -1. The slides before the
+// first section count as a section.
+func (s State) prevSection() int {
+	for i := s.SlideIndex; i >= 0; i-- {
+		if i > 0 && !s.startsSection(i) {
+			continue
+		}
+		if target := s.nextVisible(i - 1); target >= 0 && target < s.SlideIndex {
+			return target
+		}
+	}
+	return -1
+}
+
+// nextVisible returns the first slide after from that is not hidden, or -1.
+func (s State) nextVisible(from int) int {
+	for i := from + 1; i < s.TotalSlides; i++ {
+		if !s.IsHidden(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// prevVisible returns the last slide before from that is not hidden, or -1.
+func (s State) prevVisible(from int) int {
+	for i := from - 1; i >= 0; i-- {
+		if !s.IsHidden(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FirstVisible returns the first slide that is not hidden, or 0 if all are.
+func (s State) FirstVisible() int {
+	return max(s.nextVisible(-1), 0)
+}
+
+// lastVisible returns the last slide that is not hidden, or the last slide
+// if all are.
+func (s State) lastVisible() int {
+	if i := s.prevVisible(s.TotalSlides); i >= 0 {
+		return i
+	}
+	return s.TotalSlides - 1
 }
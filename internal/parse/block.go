@@ -0,0 +1,186 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// htmlBlockKind identifies which CommonMark HTML block rule opened the
+// current block, since each kind has its own end condition.
+type htmlBlockKind int
+
+const (
+	htmlNone        htmlBlockKind = iota
+	htmlRaw                       // <script>, <pre>, <style>, <textarea>
+	htmlComment                   // <!-- ... -->
+	htmlProcessing                // <? ... ?>
+	htmlDeclaration               // <!DOCTYPE ...>
+	htmlCDATA                     // <![CDATA[ ... ]]>
+	htmlBlockTag                  // known block-level tag, ends at a blank line
+	htmlOtherTag                  // any other lone tag, ends at a blank line
+)
+
+var (
+	fenceOpenRegex  = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+	fenceCloseRegex = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*$")
+
+	htmlRawRegex         = regexp.MustCompile(`(?i)^ {0,3}<(script|pre|style|textarea)(\s|>|$)`)
+	htmlCommentRegex     = regexp.MustCompile(`^ {0,3}<!--`)
+	htmlProcessingRegex  = regexp.MustCompile(`^ {0,3}<\?`)
+	htmlDeclarationRegex = regexp.MustCompile(`^ {0,3}<![A-Za-z]`)
+	htmlCDATARegex       = regexp.MustCompile(`^ {0,3}<!\[CDATA\[`)
+	htmlBlockTagRegex    = regexp.MustCompile(`(?i)^ {0,3}</?([a-z][a-z0-9]*)(\s|/?>|$)`)
+	htmlOtherTagRegex    = regexp.MustCompile(`^ {0,3}(<[A-Za-z][A-Za-z0-9-]*(\s+[A-Za-z_:][\w.:-]*(\s*=\s*("[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>)\s*$`)
+
+	atxHeadingRegex    = regexp.MustCompile(`^ {0,3}#{1,6}(\s|$)`)
+	thematicBreakRegex = regexp.MustCompile(`^ {0,3}((-\s*){3,}|(\*\s*){3,}|(_\s*){3,})$`)
+)
+
+// htmlBlockTags are the tag names that open a CommonMark type 6 HTML block.
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "base": true, "basefont": true,
+	"blockquote": true, "body": true, "caption": true, "center": true, "col": true,
+	"colgroup": true, "dd": true, "details": true, "dialog": true, "dir": true,
+	"div": true, "dl": true, "dt": true, "fieldset": true, "figcaption": true,
+	"figure": true, "footer": true, "form": true, "frame": true, "frameset": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"head": true, "header": true, "hr": true, "html": true, "iframe": true,
+	"legend": true, "li": true, "link": true, "main": true, "menu": true,
+	"menuitem": true, "nav": true, "noframes": true, "ol": true, "optgroup": true,
+	"option": true, "p": true, "param": true, "search": true, "section": true,
+	"summary": true, "table": true, "tbody": true, "td": true, "tfoot": true,
+	"th": true, "thead": true, "title": true, "tr": true, "track": true, "ul": true,
+}
+
+// blockScanner walks markdown one line at a time and reports whether each
+// line belongs to a literal block — fenced code, an HTML block or indented
+// code — whose contents must not be interpreted as slide structure.
+type blockScanner struct {
+	fence     string        // opening fence run while inside fenced code
+	html      htmlBlockKind // kind of the open HTML block, if any
+	rawTag    string        // tag name that closes an htmlRaw block
+	paragraph bool          // previous line was paragraph text
+}
+
+// literal consumes the next line and reports whether it is part of a
+// literal block, including the lines that open and close the block.
+func (s *blockScanner) literal(line string) bool {
+	if s.fence != "" {
+		if m := fenceCloseRegex.FindStringSubmatch(line); m != nil &&
+			m[1][0] == s.fence[0] && len(m[1]) >= len(s.fence) {
+			s.fence = ""
+		}
+		return true
+	}
+
+	blank := strings.TrimSpace(line) == ""
+
+	if s.html != htmlNone {
+		if s.html == htmlBlockTag || s.html == htmlOtherTag {
+			if blank {
+				s.html = htmlNone
+				return false
+			}
+			return true
+		}
+		if s.htmlEnds(line) {
+			s.html = htmlNone
+		}
+		return true
+	}
+
+	if blank {
+		s.paragraph = false
+		return false
+	}
+
+	if indentWidth(line) >= 4 && !s.paragraph {
+		return true // indented code
+	}
+
+	if m := fenceOpenRegex.FindStringSubmatch(line); m != nil {
+		if m[1][0] != '`' || !strings.Contains(m[2], "`") {
+			s.fence = m[1]
+			s.paragraph = false
+			return true
+		}
+	}
+
+	if s.openHTML(line) {
+		if s.html != htmlBlockTag && s.html != htmlOtherTag && s.htmlEnds(line) {
+			s.html = htmlNone
+		}
+		s.paragraph = false
+		return true
+	}
+
+	s.paragraph = !atxHeadingRegex.MatchString(line) && !thematicBreakRegex.MatchString(line)
+	return false
+}
+
+// inBlock reports whether the scanner is inside a multi-line literal block,
+// so the next line cannot start new structure.
+func (s *blockScanner) inBlock() bool {
+	return s.fence != "" || s.html != htmlNone
+}
+
+// openHTML reports whether line starts an HTML block, recording its kind.
+func (s *blockScanner) openHTML(line string) bool {
+	switch {
+	case htmlRawRegex.MatchString(line):
+		s.html = htmlRaw
+		s.rawTag = strings.ToLower(htmlRawRegex.FindStringSubmatch(line)[1])
+	case htmlCommentRegex.MatchString(line):
+		s.html = htmlComment
+	case htmlProcessingRegex.MatchString(line):
+		s.html = htmlProcessing
+	case htmlCDATARegex.MatchString(line):
+		s.html = htmlCDATA
+	case htmlDeclarationRegex.MatchString(line):
+		s.html = htmlDeclaration
+	case htmlBlockTagRegex.MatchString(line) &&
+		htmlBlockTags[strings.ToLower(htmlBlockTagRegex.FindStringSubmatch(line)[1])]:
+		s.html = htmlBlockTag
+	case !s.paragraph && htmlOtherTagRegex.MatchString(line):
+		// Unlike the other kinds, a lone tag cannot interrupt a paragraph.
+		s.html = htmlOtherTag
+	default:
+		return false
+	}
+	return true
+}
+
+// htmlEnds reports whether line satisfies the end condition of the open
+// HTML block.
+func (s *blockScanner) htmlEnds(line string) bool {
+	switch s.html {
+	case htmlRaw:
+		return strings.Contains(strings.ToLower(line), "</"+s.rawTag+">")
+	case htmlComment:
+		return strings.Contains(line, "-->")
+	case htmlProcessing:
+		return strings.Contains(line, "?>")
+	case htmlDeclaration:
+		return strings.Contains(line, ">")
+	case htmlCDATA:
+		return strings.Contains(line, "]]>")
+	}
+	return false
+}
+
+// indentWidth returns the visual indentation of line, expanding tabs to
+// the next multiple of four columns.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
@@ -8,34 +8,78 @@ import (
 	"github.com/jedwards1230/deck/internal/model"
 )
 
-var commentRegex = regexp.MustCompile(`<!--\s*(.*?)\s*-->`)
+var (
+	commentRegex     = regexp.MustCompile(`(?s)<!--\s*(.*?)\s*-->`)
+	commandNameRegex = regexp.MustCompile(`^([a-z][a-z0-9_]*)\s*(:|$)`)
+)
+
+// knownCommands lists every command keyword parseCommand understands.
+var knownCommands = map[string]bool{
+	"pause":         true,
+	"speaker_note":  true,
+	"column_layout": true,
+	"column":        true,
+	"reset_layout":  true,
+	"include":       true,
+	"slide":         true,
+	"row":           true,
+	"grid":          true,
+	"end_grid":      true,
+	"align":         true,
+	"valign":        true,
+	"skip":          true,
+	"toc":           true,
+	"section":       true,
+	"only":          true,
+	"end_only":      true,
+	"lang":          true,
+	"end_lang":      true,
+	"file":          true,
+	"table":         true,
+}
 
 // ExtractCommands parses HTML comments from content, returning commands and cleaned content.
 func ExtractCommands(content string) ([]CommandWithPosition, string) {
+	return extractCommands(content, func(string) bool { return true })
+}
+
+// CommandWithPosition pairs a command with its position in the content.
+type CommandWithPosition struct {
+	Command model.Command
+	Start   int // byte offset of the opening <!--
+	End     int // byte offset just past the closing -->
+}
+
+// extractCommands strips every recognized command whose inner text passes
+// keep, leaving unrecognized comments, rejected commands and comments
+// inside fenced code in place.
+func extractCommands(content string, keep func(inner string) bool) ([]CommandWithPosition, string) {
 	var commands []CommandWithPosition
+	var cleaned strings.Builder
 
-	cleaned := commentRegex.ReplaceAllStringFunc(content, func(match string) string {
-		sub := commentRegex.FindStringSubmatch(match)
-		if len(sub) < 2 {
-			return match
+	codeRanges := codeBlockRanges(content)
+	last := 0
+	for _, loc := range commentRegex.FindAllStringSubmatchIndex(content, -1) {
+		if inRanges(loc[0], codeRanges) {
+			continue
+		}
+		inner := strings.TrimSpace(content[loc[2]:loc[3]])
+		if !keep(inner) {
+			continue
 		}
 
-		inner := strings.TrimSpace(sub[1])
 		cmd, ok := parseCommand(inner)
 		if !ok {
-			return match // not a recognized command, keep as-is
+			continue // not a recognized command, keep as-is
 		}
 
-		commands = append(commands, CommandWithPosition{Command: cmd})
-		return "" // strip the command from content
-	})
+		commands = append(commands, CommandWithPosition{Command: cmd, Start: loc[0], End: loc[1]})
+		cleaned.WriteString(content[last:loc[0]]) // strip the command from content
+		last = loc[1]
+	}
+	cleaned.WriteString(content[last:])
 
-	return commands, cleaned
-}
-
-// CommandWithPosition pairs a command with its position in the content.
-type CommandWithPosition struct {
-	Command model.Command
+	return commands, cleaned.String()
 }
 
 func parseCommand(s string) (model.Command, bool) {
@@ -43,9 +87,10 @@ func parseCommand(s string) (model.Command, bool) {
 	case s == "pause":
 		return model.Command{Type: model.CmdPause}, true
 
-	case strings.HasPrefix(s, "speaker_note:"):
-		note := strings.TrimSpace(strings.TrimPrefix(s, "speaker_note:"))
-		return model.Command{Type: model.CmdSpeakerNote, Value: note}, true
+	case isNoteCommand(s):
+		note := strings.TrimLeft(strings.TrimPrefix(s, "speaker_note"), " \t")
+		note = strings.TrimPrefix(note, ":")
+		return model.Command{Type: model.CmdSpeakerNote, Value: blockValue(note)}, true
 
 	case strings.HasPrefix(s, "column_layout:"):
 		ratioStr := strings.TrimSpace(strings.TrimPrefix(s, "column_layout:"))
@@ -63,14 +108,135 @@ func parseCommand(s string) (model.Command, bool) {
 		}
 		return model.Command{Type: model.CmdColumn, Column: col}, true
 
+	case s == "skip":
+		return model.Command{Type: model.CmdSkip}, true
+
+	case s == "toc":
+		return model.Command{Type: model.CmdTOC}, true
+
+	case strings.HasPrefix(s, "toc:"):
+		opts := strings.TrimSpace(strings.TrimPrefix(s, "toc:"))
+		if opts == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdTOC, Value: opts}, true
+
+	case strings.HasPrefix(s, "section:"):
+		name := strings.TrimSpace(strings.TrimPrefix(s, "section:"))
+		if name == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdSection, Value: name}, true
+
+	case strings.HasPrefix(s, "only:"):
+		profiles := strings.TrimSpace(strings.TrimPrefix(s, "only:"))
+		if profiles == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdOnly, Value: profiles}, true
+
+	case s == "end_only":
+		return model.Command{Type: model.CmdEndOnly}, true
+
+	case strings.HasPrefix(s, "lang:"):
+		lang := strings.TrimSpace(strings.TrimPrefix(s, "lang:"))
+		if lang == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdLang, Value: lang}, true
+
+	case s == "end_lang":
+		return model.Command{Type: model.CmdEndLang}, true
+
 	case s == "reset_layout":
 		return model.Command{Type: model.CmdResetLayout}, true
 
+	case s == "row":
+		return model.Command{Type: model.CmdRow, Ratios: []int{1}}, true
+
+	case strings.HasPrefix(s, "row:"):
+		ratios := parseRatios(strings.TrimPrefix(s, "row:"))
+		if len(ratios) == 0 {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdRow, Ratios: ratios}, true
+
+	case s == "grid":
+		return model.Command{Type: model.CmdGrid}, true
+
+	case s == "end_grid":
+		return model.Command{Type: model.CmdEndGrid}, true
+
+	case strings.HasPrefix(s, "align:"):
+		align := strings.TrimSpace(strings.TrimPrefix(s, "align:"))
+		if !model.ValidAlign(align) {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdAlign, Value: align}, true
+
+	case strings.HasPrefix(s, "valign:"):
+		valign := strings.TrimSpace(strings.TrimPrefix(s, "valign:"))
+		if !model.ValidVAlign(valign) {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdVAlign, Value: valign}, true
+
+	case strings.HasPrefix(s, "include:"):
+		path := strings.TrimSpace(strings.TrimPrefix(s, "include:"))
+		if path == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdInclude, Value: path}, true
+
+	case strings.HasPrefix(s, "file:"):
+		path := strings.TrimSpace(strings.TrimPrefix(s, "file:"))
+		if path == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdFile, Value: path}, true
+
+	case strings.HasPrefix(s, "table:"):
+		spec := strings.TrimSpace(strings.TrimPrefix(s, "table:"))
+		if spec == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdTable, Value: spec}, true
+
+	case strings.HasPrefix(s, "slide:"):
+		meta := strings.TrimSpace(strings.TrimPrefix(s, "slide:"))
+		if meta == "" {
+			return model.Command{}, false
+		}
+		return model.Command{Type: model.CmdSlideMeta, Value: meta}, true
+
 	default:
 		return model.Command{}, false
 	}
 }
 
+// isNoteCommand reports whether s is a speaker_note command: the keyword
+// followed by a colon, or alone on the comment's first line with the note
+// on the lines below.
+func isNoteCommand(s string) bool {
+	rest, ok := strings.CutPrefix(s, "speaker_note")
+	if !ok {
+		return false
+	}
+	rest = strings.TrimLeft(rest, " \t")
+	return rest != "" && (rest[0] == ':' || rest[0] == '\n' || rest[0] == '\r')
+}
+
+// commandName returns the keyword of a comment that is shaped like a
+// command — a lowercase snake_case word, alone or followed by a colon —
+// so ordinary comments such as "TODO: ..." are not mistaken for typos.
+func commandName(inner string) (string, bool) {
+	m := commandNameRegex.FindStringSubmatch(inner)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
 func parseRatios(s string) []int {
 	// Parse [3,2] or [1, 2, 3] format
 	s = strings.TrimSpace(s)
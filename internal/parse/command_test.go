@@ -29,6 +29,37 @@ func TestExtractCommands(t *testing.T) {
 			},
 			wantCleaned: "Content\n",
 		},
+		{
+			name:  "speaker note block",
+			input: "Content\n<!-- speaker_note\n  - **first**\n    - nested\n  - second\n-->\nMore",
+			wantCmds: []model.Command{
+				{Type: model.CmdSpeakerNote, Value: "- **first**\n  - nested\n- second"},
+			},
+			wantCleaned: "Content\n\nMore",
+		},
+		{
+			name:  "speaker note block after colon",
+			input: "<!-- speaker_note: Intro\n\n    Then this -->",
+			wantCmds: []model.Command{
+				{Type: model.CmdSpeakerNote, Value: "Intro\n\nThen this"},
+			},
+			wantCleaned: "",
+		},
+		{
+			name:        "commands inside fenced code are content",
+			input:       "```markdown\n<!-- slide: {id: a} -->\n```\n<!-- slide: {id: b} -->",
+			wantCmds:    []model.Command{{Type: model.CmdSlideMeta, Value: "{id: b}"}},
+			wantCleaned: "```markdown\n<!-- slide: {id: a} -->\n```\n",
+		},
+		{
+			name:  "alignment commands",
+			input: "<!-- align: center -->\n<!-- valign: bottom -->\n<!-- align: middle -->",
+			wantCmds: []model.Command{
+				{Type: model.CmdAlign, Value: "center"},
+				{Type: model.CmdVAlign, Value: "bottom"},
+			},
+			wantCleaned: "\n\n<!-- align: middle -->",
+		},
 		{
 			name:  "column layout with ratios",
 			input: "<!-- column_layout: [3, 2] -->",
@@ -152,6 +183,19 @@ func TestExtractCommands(t *testing.T) {
 	}
 }
 
+func TestExtractCommandsPositions(t *testing.T) {
+	input := "Intro\n<!-- pause -->\n<!-- TODO -->\n<!-- speaker_note: hi -->"
+	cmds, _ := ExtractCommands(input)
+	if len(cmds) != 2 {
+		t.Fatalf("len(commands) = %d, want 2", len(cmds))
+	}
+	for i, want := range []string{"<!-- pause -->", "<!-- speaker_note: hi -->"} {
+		if got := input[cmds[i].Start:cmds[i].End]; got != want {
+			t.Errorf("cmd[%d] covers %q, want %q", i, got, want)
+		}
+	}
+}
+
 func TestParseRatios(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -0,0 +1,100 @@
+package parse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadDeck reads the deck at path for Parse. A file is read as is. A
+// directory is presented as one deck made of its *.md files in natural
+// filename order, so 2-setup.md comes before 10-demo.md; the returned
+// content is a frontmatter block listing them as slides.
+func ReadDeck(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	files, err := deckFiles(path)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no .md files in %s", path)
+	}
+	var b strings.Builder
+	b.WriteString("---\nslides:\n")
+	for _, name := range files {
+		fmt.Fprintf(&b, "  - %s\n", strconv.Quote(name))
+	}
+	b.WriteString("---\n")
+	return b.String(), nil
+}
+
+// IsDeckFile reports whether a file with this name is part of a directory
+// deck.
+func IsDeckFile(name string) bool {
+	name = filepath.Base(name)
+	return filepath.Ext(name) == ".md" && !strings.HasPrefix(name, ".")
+}
+
+// deckFiles returns the names of the deck files in dir in natural order.
+func deckFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && IsDeckFile(e.Name()) {
+			files = append(files, e.Name())
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return naturalLess(files[i], files[j])
+	})
+	return files, nil
+}
+
+// naturalLess orders strings with runs of digits compared by value, so
+// "slide2" sorts before "slide10".
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := digitPrefix(a), digitPrefix(b)
+		if da != "" && db != "" {
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
@@ -0,0 +1,109 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadDeckDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"10-outro.md":  "# Outro\n",
+		"2-middle.md":  "# Middle\n---\n# Middle 2\n",
+		"1-intro.md":   "---\nauthor: Dir\n---\n# Intro\n",
+		"notes.txt":    "not a slide",
+		".draft.md":    "# Hidden draft\n",
+		"sub/extra.md": "# Not included\n",
+	})
+
+	content, err := ReadDeck(dir)
+	if err != nil {
+		t.Fatalf("ReadDeck() error = %v", err)
+	}
+	pres := Parse(content, Options{Path: dir})
+
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	var titles []string
+	for _, s := range pres.Slides {
+		titles = append(titles, strings.TrimSpace(s.Chunks[0].Content))
+	}
+	want := "# Intro|# Middle|# Middle 2|# Outro"
+	if got := strings.Join(titles, "|"); got != want {
+		t.Errorf("slides = %q, want %q", got, want)
+	}
+	if pres.Frontmatter.Author != "Dir" {
+		t.Errorf("author = %q, want Dir", pres.Frontmatter.Author)
+	}
+	if got := pres.Slides[3].Span.File; got != filepath.Join(dir, "10-outro.md") {
+		t.Errorf("last slide file = %q", got)
+	}
+}
+
+func TestReadDeckEmptyDirectory(t *testing.T) {
+	if _, err := ReadDeck(t.TempDir()); err == nil {
+		t.Error("ReadDeck() of an empty directory succeeded, want error")
+	}
+}
+
+func TestReadDeckFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(path, []byte("# One\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ReadDeck(path)
+	if err != nil || content != "# One\n" {
+		t.Errorf("ReadDeck() = %q, %v", content, err)
+	}
+}
+
+func TestParseSlidesList(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"deck.md":    "---\nslides:\n  - parts/a.md\n  - missing.md\n  - parts/b.md\n---\n# Title\n",
+		"parts/a.md": "# A\n",
+		"parts/b.md": "# B\n---\n# B2\n",
+	})
+	pres := parseFile(t, filepath.Join(dir, "deck.md"))
+
+	var titles []string
+	for _, s := range pres.Slides {
+		titles = append(titles, strings.TrimSpace(s.Chunks[0].Content))
+	}
+	want := "# Title|# A|# B|# B2"
+	if got := strings.Join(titles, "|"); got != want {
+		t.Errorf("slides = %q, want %q", got, want)
+	}
+	if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != "include-missing" {
+		t.Fatalf("diagnostics = %v, want one include-missing", diagCodes(pres))
+	}
+	if got := pres.Diagnostics[0].Span.StartLine; got != 4 {
+		t.Errorf("missing file line = %d, want 4", got)
+	}
+	if want := filepath.Join(dir, "missing.md"); len(pres.Missing) != 1 || pres.Missing[0] != want {
+		t.Errorf("missing = %q, want %s", pres.Missing, want)
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"2-a.md", "10-a.md", true},
+		{"10-a.md", "2-a.md", false},
+		{"slide9.md", "slide10.md", true},
+		{"01.md", "1.md", false},
+		{"a.md", "b.md", true},
+		{"intro.md", "intro.md", false},
+		{"a.md", "a1.md", true},
+	}
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package parse
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// lineRangeRegex matches a file directive's line selector, L40-L72 or L40.
+var lineRangeRegex = regexp.MustCompile(`^L(\d+)(?:-L?(\d+))?$`)
+
+// excerptLanguages maps file extensions, and the names of files without
+// one, to the language of their code blocks where it is not the extension
+// itself.
+var excerptLanguages = map[string]string{
+	".py":        "python",
+	".js":        "javascript",
+	".mjs":       "javascript",
+	".ts":        "typescript",
+	".rb":        "ruby",
+	".rs":        "rust",
+	".sh":        "bash",
+	".h":         "c",
+	".cc":        "cpp",
+	".hpp":       "cpp",
+	".kt":        "kotlin",
+	".cs":        "csharp",
+	".yml":       "yaml",
+	".md":        "markdown",
+	".proto":     "protobuf",
+	".tf":        "hcl",
+	"Dockerfile": "dockerfile",
+	"Makefile":   "makefile",
+}
+
+// excerpt writes a fenced code block holding the part of the file at path,
+// relative to from, that the file directive selects: the whole file, a
+// line range such as #L40-L72, or in a Go file a declaration such as
+// #func:handleRequest, #func:Server.Start or #type:Config, with its doc
+// comment. The file becomes a source of the deck so edits to it reload the
+// slide. Failures are reported at the directive and leave an empty line
+// behind.
+func (p *parser) excerpt(from, spec string, directive model.Span) {
+	path, selector := spec, ""
+	if i := strings.LastIndex(spec, "#"); i >= 0 {
+		path, selector = spec[:i], spec[i+1:]
+	}
+	resolved := path
+	if !filepath.IsAbs(path) {
+		resolved = filepath.Join(p.baseDir(from), path)
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "file-missing", directive,
+			"cannot read %s: %v", path, unwrapPathError(err))
+		p.addMissing(resolved)
+		return
+	}
+	p.addSource(resolved)
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	p.src.addFile(resolved, content)
+
+	start, end, err := selectExcerpt(resolved, content, selector)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "file-excerpt", directive, "file %s: %v", spec, err)
+		return
+	}
+
+	code := unindent(strings.TrimRight(content[start:end], "\n"))
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	// The fences map to the directive's line and the code to the file it
+	// came from.
+	p.src.write(from, directive.Start, fence+excerptLanguage(resolved)+"\n")
+	p.src.write(resolved, start, code+"\n")
+	p.src.write(from, max(directive.Start, directive.End-len(fence)), fence)
+}
+
+// unindent removes the leading whitespace shared by every non-blank line
+// of code, so an excerpt from inside a block starts at the margin.
+func unindent(code string) string {
+	lines := strings.Split(code, "\n")
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = lead, false
+			continue
+		}
+		for !strings.HasPrefix(lead, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if prefix == "" {
+		return code
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// excerptLanguage returns the code block language for the file at path.
+func excerptLanguage(path string) string {
+	base, ext := filepath.Base(path), filepath.Ext(path)
+	if lang, ok := excerptLanguages[base]; ok {
+		return lang
+	}
+	if lang, ok := excerptLanguages[ext]; ok {
+		return lang
+	}
+	return strings.TrimPrefix(ext, ".")
+}
+
+// selectExcerpt returns the byte range of content that selector picks.
+func selectExcerpt(path, content, selector string) (int, int, error) {
+	if selector == "" {
+		return 0, len(content), nil
+	}
+	if m := lineRangeRegex.FindStringSubmatch(selector); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		last := first
+		if m[2] != "" {
+			last, _ = strconv.Atoi(m[2])
+		}
+		return lineRange(content, first, last)
+	}
+	kind, name, ok := strings.Cut(selector, ":")
+	if !ok || (kind != "func" && kind != "type") || name == "" {
+		return 0, 0, fmt.Errorf("unknown selector %q; use #L10-L20, #func:Name or #type:Name", selector)
+	}
+	if filepath.Ext(path) != ".go" {
+		return 0, 0, fmt.Errorf("#%s selects a Go declaration, and %s is not a Go file", selector, filepath.Base(path))
+	}
+	return goDeclaration(path, content, kind, name)
+}
+
+// lineRange returns the byte range of lines first through last of
+// content, 1-based and inclusive, without the final newline.
+func lineRange(content string, first, last int) (int, int, error) {
+	lines := strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+	if first < 1 || last < first || last > lines {
+		return 0, 0, fmt.Errorf("lines %d-%d are outside the file's %d lines", first, last, lines)
+	}
+	start := 0
+	for range first - 1 {
+		start += strings.IndexByte(content[start:], '\n') + 1
+	}
+	end := start
+	for range last - first + 1 {
+		i := strings.IndexByte(content[end:], '\n')
+		if i < 0 {
+			return start, len(content), nil
+		}
+		end += i + 1
+	}
+	return start, end - 1, nil
+}
+
+// goDeclaration returns the byte range of the Go func or type named name,
+// from the start of its doc comment's line to the end of its last line. A
+// method is named Type.Method.
+func goDeclaration(path, content, kind, name string) (int, int, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, path, content, goparser.ParseComments|goparser.SkipObjectResolution)
+	if file == nil {
+		return 0, 0, err
+	}
+
+	var node ast.Node
+	var doc *ast.CommentGroup
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if kind == "func" && funcName(d) == name {
+				node, doc = d, d.Doc
+			}
+		case *ast.GenDecl:
+			if kind != "type" || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != name {
+					continue
+				}
+				// A type in a grouped declaration is taken alone.
+				node, doc = ts, ts.Doc
+				if len(d.Specs) == 1 {
+					node, doc = d, d.Doc
+				}
+			}
+		}
+		if node != nil {
+			break
+		}
+	}
+	if node == nil {
+		return 0, 0, fmt.Errorf("%s %s is not declared in %s", kind, name, filepath.Base(path))
+	}
+
+	pos := node.Pos()
+	if doc != nil {
+		pos = doc.Pos()
+	}
+	start := fset.Position(pos).Offset
+	start = strings.LastIndexByte(content[:start], '\n') + 1
+	end := fset.Position(node.End()).Offset
+	if i := strings.IndexByte(content[end:], '\n'); i >= 0 {
+		end += i
+	} else {
+		end = len(content)
+	}
+	return start, end, nil
+}
+
+// funcName returns the name a file directive selects a func by: Name for
+// a function and Type.Name for a method.
+func funcName(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return d.Name.Name
+	}
+	typ := d.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return t.Name + "." + d.Name.Name
+		default:
+			return d.Name.Name
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package parse
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jedwards1230/deck/internal/code"
+)
+
+const serverSource = `package main
+
+import "net/http"
+
+// Config holds the server settings.
+type Config struct {
+	Addr string
+}
+
+type (
+	// Handler answers one route.
+	Handler func(w http.ResponseWriter, r *http.Request)
+	Routes  map[string]Handler
+)
+
+// Server serves the API.
+type Server struct{ cfg Config }
+
+// handleRequest answers every request.
+func handleRequest(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+// Start listens on the configured address.
+func (s *Server) Start() error {
+	return http.ListenAndServe(s.cfg.Addr, nil)
+}
+`
+
+func TestParseFileExcerpt(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		wantLang  string
+		wantCode  string
+	}{
+		{
+			name:      "line range",
+			directive: "../cmd/server/main.go#L19-L22",
+			wantLang:  "go",
+			wantCode:  "// handleRequest answers every request.\nfunc handleRequest(w http.ResponseWriter, r *http.Request) {\n\tw.Write([]byte(\"ok\"))\n}",
+		},
+		{
+			name:      "single indented line",
+			directive: "../cmd/server/main.go#L21",
+			wantLang:  "go",
+			wantCode:  "w.Write([]byte(\"ok\"))",
+		},
+		{
+			name:      "function",
+			directive: "../cmd/server/main.go#func:handleRequest",
+			wantLang:  "go",
+			wantCode:  "// handleRequest answers every request.\nfunc handleRequest(w http.ResponseWriter, r *http.Request) {\n\tw.Write([]byte(\"ok\"))\n}",
+		},
+		{
+			name:      "method",
+			directive: "../cmd/server/main.go#func:Server.Start",
+			wantLang:  "go",
+			wantCode:  "// Start listens on the configured address.\nfunc (s *Server) Start() error {\n\treturn http.ListenAndServe(s.cfg.Addr, nil)\n}",
+		},
+		{
+			name:      "type",
+			directive: "../cmd/server/main.go#type:Config",
+			wantLang:  "go",
+			wantCode:  "// Config holds the server settings.\ntype Config struct {\n\tAddr string\n}",
+		},
+		{
+			name:      "type in a group",
+			directive: "../cmd/server/main.go#type:Handler",
+			wantLang:  "go",
+			wantCode:  "// Handler answers one route.\nHandler func(w http.ResponseWriter, r *http.Request)",
+		},
+		{
+			name:      "whole file",
+			directive: "../scripts/deploy.sh",
+			wantLang:  "bash",
+			wantCode:  "#!/bin/sh\necho deploying",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"cmd/server/main.go": serverSource,
+				"scripts/deploy.sh":  "#!/bin/sh\necho deploying\n",
+				"talk/deck.md":       "# Walkthrough\n\n<!-- file: " + tt.directive + " -->\n\nThat's it.\n",
+			})
+
+			pres := parseFile(t, filepath.Join(dir, "talk", "deck.md"))
+			if len(pres.Diagnostics) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+			}
+			content := pres.Slides[0].VisibleContent(0)
+			blocks := code.ExtractBlocks(content)
+			if len(blocks) != 1 || blocks[0].Language != tt.wantLang || blocks[0].Code != tt.wantCode {
+				t.Fatalf("blocks = %+v, want one %s block with %q\ncontent:\n%s", blocks, tt.wantLang, tt.wantCode, content)
+			}
+			if !strings.Contains(content, "That's it.") {
+				t.Errorf("content = %q, want the text after the directive", content)
+			}
+
+			block := pres.Slides[0].CodeBlocks[0]
+			if block.Span.StartLine != 3 || block.Span.EndLine != 3 {
+				t.Errorf("code block span = %+v, want the directive's line", block.Span)
+			}
+			source := filepath.Join(dir, "cmd", "server", "main.go")
+			if tt.wantLang == "bash" {
+				source = filepath.Join(dir, "scripts", "deploy.sh")
+			}
+			if !slices.Contains(pres.Sources, source) {
+				t.Errorf("sources = %v, want %s watched", pres.Sources, source)
+			}
+		})
+	}
+}
+
+func TestParseFileExcerptErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		wantCode  string
+	}{
+		{"missing file", "nope.go", "file-missing"},
+		{"lines past the end", "main.go#L30-L99", "file-excerpt"},
+		{"undeclared func", "main.go#func:nope", "file-excerpt"},
+		{"method named without its type", "main.go#func:Start", "file-excerpt"},
+		{"symbol in a non-Go file", "notes.txt#func:main", "file-excerpt"},
+		{"unknown selector", "main.go#var:x", "file-excerpt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"main.go":   serverSource,
+				"notes.txt": "func main\n",
+				"deck.md":   "# One\n\n<!-- file: " + tt.directive + " -->\n",
+			})
+
+			pres := parseFile(t, filepath.Join(dir, "deck.md"))
+			if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != tt.wantCode ||
+				pres.Diagnostics[0].Span.StartLine != 3 {
+				t.Fatalf("diagnostics = %v, want one %s on line 3", pres.Diagnostics, tt.wantCode)
+			}
+			if len(pres.Slides[0].CodeBlocks) != 0 {
+				t.Errorf("a failed excerpt should leave no code block")
+			}
+		})
+	}
+}
+
+func TestParseFileExcerptInCode(t *testing.T) {
+	pres := ParsePresentation("# One\n\n```markdown\n<!-- file: main.go#L1-L3 -->\n```\n\nSee <!-- file: main.go --> inline.")
+	if got := diagCodes(pres); !slices.Equal(got, []string{"inline-file"}) {
+		t.Errorf("diagnostics = %v, want only inline-file", got)
+	}
+	if !strings.Contains(pres.Slides[0].VisibleContent(0), "<!-- file: main.go#L1-L3 -->") {
+		t.Errorf("a directive in a code block should be kept as written")
+	}
+}
@@ -0,0 +1,162 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+var (
+	footnoteDefRegex = regexp.MustCompile(`^ {0,3}\[\^([^\]\s]+)\]:[ \t]*(.*)$`)
+	footnoteRefRegex = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+)
+
+// footnoteDef is a footnote definition taken from a slide.
+type footnoteDef struct {
+	label, text string
+	start, end  int
+}
+
+// extractFootnotes removes the [^label]: text definitions outside literal
+// blocks from raw, returning them in source order. Indented lines after a
+// definition continue it. The definitions are blanked rather than cut so
+// offsets into raw stay valid.
+func (p *parser) extractFootnotes(raw string, base int) (string, []footnoteDef) {
+	var defs []footnoteDef
+	var scanner blockScanner
+	blanked := blanker(raw)
+
+	open := false
+	offset := 0
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		start := offset
+		offset += len(line)
+		text := strings.TrimRight(line, "\r\n")
+		if scanner.literal(text) {
+			open = false
+			continue
+		}
+
+		if open && strings.TrimSpace(text) != "" && indentWidth(text) >= 2 {
+			def := &defs[len(defs)-1]
+			def.text += " " + strings.TrimSpace(text)
+			def.end = start + len(text)
+			blanked.blank(start, def.end)
+			continue
+		}
+		open = false
+
+		m := footnoteDefRegex.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		for _, def := range defs {
+			if def.label == m[1] {
+				p.report(model.SeverityWarning, "duplicate-footnote", base+start, base+start+len(text),
+					"footnote [^%s] is already defined on this slide; only the first definition is used", m[1])
+				break
+			}
+		}
+		defs = append(defs, footnoteDef{label: m[1], text: strings.TrimSpace(m[2]), start: start, end: start + len(text)})
+		blanked.blank(start, start+len(text))
+		open = true
+	}
+	return string(blanked), defs
+}
+
+// addFootnotes numbers the slide's footnotes in order of first reference
+// outside code, so references read 1, 2, 3 down the slide and through its
+// cells, then replaces each reference with its number in superscript.
+// A footnote appears from the chunk that first references it. References
+// without a definition are left as written.
+func (p *parser) addFootnotes(slide *model.Slide, raw string, base int, defs []footnoteDef, codeRanges [][2]int, pauses [][]int) {
+	if len(defs) == 0 && !strings.Contains(raw, "[^") {
+		return
+	}
+
+	byLabel := make(map[string]footnoteDef, len(defs))
+	for _, def := range defs {
+		if _, ok := byLabel[def.label]; !ok {
+			byLabel[def.label] = def
+		}
+	}
+
+	spans := inlineCodeSpans(raw)
+	markers := make(map[string]string)
+	undefined := make(map[string]bool)
+	for _, loc := range footnoteRefRegex.FindAllStringSubmatchIndex(raw, -1) {
+		if inRanges(loc[0], codeRanges) || inRanges(loc[0], spans) {
+			continue
+		}
+		label := raw[loc[2]:loc[3]]
+		if _, ok := markers[label]; ok {
+			continue
+		}
+		def, ok := byLabel[label]
+		if !ok {
+			if !undefined[label] {
+				undefined[label] = true
+				p.report(model.SeverityWarning, "undefined-footnote", base+loc[0], base+loc[1],
+					"footnote [^%s] is not defined on this slide", label)
+			}
+			continue
+		}
+
+		chunk := 0
+		for _, pause := range pauses {
+			if pause[1] <= loc[0] {
+				chunk++
+			}
+		}
+		f := model.Footnote{
+			Label:  label,
+			Number: len(slide.Footnotes) + 1,
+			Text:   def.text,
+			Chunk:  chunk,
+			Span:   p.src.span(base+def.start, base+def.end),
+		}
+		slide.Footnotes = append(slide.Footnotes, f)
+		markers[label] = f.Marker()
+	}
+
+	for _, def := range defs {
+		if _, ok := markers[def.label]; !ok && byLabel[def.label] == def {
+			p.report(model.SeverityWarning, "unused-footnote", base+def.start, base+def.end,
+				"footnote [^%s] is never referenced on this slide", def.label)
+		}
+	}
+
+	if len(markers) == 0 {
+		return
+	}
+	for i := range slide.Chunks {
+		chunk := &slide.Chunks[i]
+		chunk.Content = replaceFootnoteRefs(chunk.Content, markers)
+		for j := range chunk.Cells {
+			chunk.Cells[j] = replaceFootnoteRefs(chunk.Cells[j], markers)
+		}
+	}
+	for i := range slide.Columns {
+		slide.Columns[i] = replaceFootnoteRefs(slide.Columns[i], markers)
+	}
+}
+
+// replaceFootnoteRefs replaces the references to footnotes in markers
+// outside code with their markers.
+func replaceFootnoteRefs(s string, markers map[string]string) string {
+	codeRanges, spans := codeBlockRanges(s), inlineCodeSpans(s)
+	var b strings.Builder
+	prev := 0
+	for _, loc := range footnoteRefRegex.FindAllStringSubmatchIndex(s, -1) {
+		marker, ok := markers[s[loc[2]:loc[3]]]
+		if !ok || inRanges(loc[0], codeRanges) || inRanges(loc[0], spans) {
+			continue
+		}
+		b.WriteString(s[prev:loc[0]])
+		b.WriteString(marker)
+		prev = loc[1]
+	}
+	b.WriteString(s[prev:])
+	return b.String()
+}
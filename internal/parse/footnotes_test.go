@@ -0,0 +1,96 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFootnotes(t *testing.T) {
+	input := "# Results\n\n" +
+		"Accuracy rose sharply[^smith], as predicted[^1].\n\n" +
+		"<!-- pause -->\n\n" +
+		"Replicated later[^jones] and again[^smith].\n\n" +
+		"`[^smith]` stays as written.\n\n" +
+		"[^1]: Our own pilot.\n" +
+		"[^smith]: Smith et al., *Nature*, 2021.\n" +
+		"[^jones]: Jones, 2023,\n" +
+		"    with a long tail.\n" +
+		"```markdown\n[^1]: kept in code\n```"
+
+	pres := ParsePresentation(input)
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	slide := pres.Slides[0]
+
+	var got []string
+	for _, f := range slide.Footnotes {
+		got = append(got, f.Marker()+f.Label+":"+f.Text)
+	}
+	want := "¹smith:Smith et al., *Nature*, 2021.|²1:Our own pilot.|³jones:Jones, 2023, with a long tail."
+	if strings.Join(got, "|") != want {
+		t.Errorf("footnotes = %s, want %s", strings.Join(got, "|"), want)
+	}
+
+	content := slide.VisibleContent(1)
+	for _, text := range []string{"sharply¹", "predicted²", "later³", "again¹", "`[^smith]`", "[^1]: kept in code"} {
+		if !strings.Contains(content, text) {
+			t.Errorf("content = %q, want it to contain %q", content, text)
+		}
+	}
+	if strings.Contains(content, "Our own pilot") {
+		t.Errorf("content = %q, want the definitions removed", content)
+	}
+
+	if n := len(slide.VisibleFootnotes(0)); n != 2 {
+		t.Errorf("first step shows %d footnotes, want 2", n)
+	}
+	if n := len(slide.VisibleFootnotes(1)); n != 3 {
+		t.Errorf("second step shows %d footnotes, want 3", n)
+	}
+}
+
+func TestParseFootnotesInColumns(t *testing.T) {
+	input := "# Compare\n\n" +
+		"<!-- column_layout: [1, 1] -->\n<!-- column: 0 -->\nOld method[^a].\n\n[^a]: Lee, 2019.\n" +
+		"<!-- column: 1 -->\nNew method[^b], beating Lee[^a].\n\n[^b]: Kim, 2024.\n" +
+		"<!-- reset_layout -->"
+
+	pres := ParsePresentation(input)
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	slide := pres.Slides[0]
+	if len(slide.Footnotes) != 2 || slide.Footnotes[0].Label != "a" || slide.Footnotes[1].Label != "b" {
+		t.Fatalf("footnotes = %+v, want a then b", slide.Footnotes)
+	}
+	columns := slide.VisibleColumns(0)
+	if !strings.Contains(columns[1], "method¹") || !strings.Contains(columns[2], "method²") ||
+		!strings.Contains(columns[2], "Lee¹") || strings.Contains(columns[1], "Lee, 2019") {
+		t.Errorf("columns = %q, want numbered references and no definitions", columns)
+	}
+}
+
+func TestParseFootnoteDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantLine int
+	}{
+		{"undefined", "# One\n\nClaim[^x].", "undefined-footnote", 3},
+		{"unused", "# One\n\nText.\n\n[^x]: Never cited.", "unused-footnote", 5},
+		{"duplicate", "# One\n\nClaim[^x].\n\n[^x]: First.\n[^x]: Second.", "duplicate-footnote", 6},
+		{"defined on another slide", "# One\n\n[^x]: Here.\n---\n# Two\n\nClaim[^x].", "unused-footnote", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := ParsePresentation(tt.input)
+			if len(pres.Diagnostics) == 0 || pres.Diagnostics[0].Code != tt.wantCode ||
+				pres.Diagnostics[0].Span.StartLine != tt.wantLine {
+				t.Fatalf("diagnostics = %v, want %s on line %d first", pres.Diagnostics, tt.wantCode, tt.wantLine)
+			}
+		})
+	}
+}
@@ -1,38 +1,148 @@
 package parse
 
 import (
+	"reflect"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/jedwards1230/deck/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
-// ParseFrontmatter extracts YAML frontmatter from content.
-// Returns the frontmatter and remaining content (without the frontmatter block).
-func ParseFrontmatter(content string) (model.Frontmatter, string) {
-	var fm model.Frontmatter
-
-	trimmed := strings.TrimSpace(content)
-	if !strings.HasPrefix(trimmed, "---") {
-		return fm, content
+// frontmatterKeys lists the keys model.Frontmatter decodes; others are kept
+// in its Extra map.
+var frontmatterKeys = func() []string {
+	var keys []string
+	t := reflect.TypeOf(model.Frontmatter{})
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" {
+			keys = append(keys, name)
+		}
 	}
+	return keys
+}()
 
-	// Find closing ---
-	rest := trimmed[3:] // skip opening ---
-	idx := strings.Index(rest, "\n---")
-	if idx < 0 {
-		return fm, content
+// checkFrontmatterKeys reports the keys of a frontmatter block that deck
+// does not know: all of them in strict mode, otherwise only those close
+// enough to a known key to be a typo.
+func (p *parser) checkFrontmatterKeys(file string, block frontmatterBlock) {
+	var doc yaml.Node
+	if yaml.Unmarshal([]byte(block.yaml), &doc) != nil || len(doc.Content) == 0 {
+		return
+	}
+	firstLine := p.src.line(file, block.yamlStart)
+	fields := doc.Content[0].Content
+	for i := 0; i+1 < len(fields); i += 2 {
+		key := fields[i].Value
+		if slices.Contains(frontmatterKeys, key) {
+			continue
+		}
+		span := p.src.lineSpan(file, firstLine+fields[i].Line-1)
+		suggestion := closestKey(key, frontmatterKeys)
+		switch {
+		case p.strict && suggestion != "":
+			p.reportSpan(model.SeverityError, "frontmatter-key", span, "unknown frontmatter key %q; did you mean %q?", key, suggestion)
+		case p.strict:
+			p.reportSpan(model.SeverityError, "frontmatter-key", span, "unknown frontmatter key %q", key)
+		case suggestion != "":
+			p.reportSpan(model.SeverityWarning, "frontmatter-key", span, "unknown frontmatter key %q; did you mean %q?", key, suggestion)
+		}
 	}
+}
 
-	yamlContent := rest[:idx]
-	remaining := strings.TrimPrefix(rest[idx+4:], "\n")
+// closestKey returns the key closest to s within two edits, or one for
+// short keys, or "" when none is that close.
+func closestKey(s string, keys []string) string {
+	best, bestDist := "", 3
+	if len(s) < 5 {
+		bestDist = 2
+	}
+	for _, key := range keys {
+		if d := editDistance(s, key); d < bestDist {
+			best, bestDist = key, d
+		}
+	}
+	return best
+}
 
-	_ = yaml.Unmarshal([]byte(yamlContent), &fm)
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
 
-	// Apply defaults
+func applyFrontmatterDefaults(fm *model.Frontmatter) {
 	if fm.Paging == "" {
 		fm.Paging = "Slide %d / %d"
 	}
+}
+
+// frontmatterBlock is a located frontmatter block.
+type frontmatterBlock struct {
+	yaml      string
+	yamlStart int // offset of the first YAML byte
+	bodyStart int // offset just past the closing delimiter line
+}
+
+// openingDelimiter reports whether content (ignoring leading whitespace)
+// starts with a --- line, returning the offsets where that line starts and
+// where the line after it begins.
+func openingDelimiter(content string) (start, next int, ok bool) {
+	start = len(content) - len(strings.TrimLeftFunc(content, unicode.IsSpace))
+	first, _, _ := strings.Cut(content[start:], "\n")
+	if strings.TrimRight(first, " \t") != "---" {
+		return 0, 0, false
+	}
+	next = start + len(first)
+	if next < len(content) {
+		next++ // the newline
+	}
+	return start, next, true
+}
+
+// splitFrontmatter locates a --- delimited block at the start of content,
+// ignoring leading whitespace. The closing delimiter must be a --- line
+// outside any code fence or HTML block, so a deck that merely opens with a
+// slide rule is not cut at a --- inside a later code example.
+func splitFrontmatter(content string) (frontmatterBlock, bool) {
+	_, offset, ok := openingDelimiter(content)
+	if !ok {
+		return frontmatterBlock{}, false
+	}
+
+	var scanner blockScanner
+	yamlStart := offset
+	for _, raw := range strings.SplitAfter(content[offset:], "\n") {
+		line := strings.TrimSuffix(raw, "\n")
+		lineStart := offset
+		offset += len(raw)
+		if scanner.literal(line) {
+			continue
+		}
+		if strings.TrimRight(line, " \t") == "---" {
+			return frontmatterBlock{
+				yaml:      content[yamlStart:lineStart],
+				yamlStart: yamlStart,
+				bodyStart: offset,
+			}, true
+		}
+	}
 
-	return fm, remaining
+	return frontmatterBlock{}, false
 }
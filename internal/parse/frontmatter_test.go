@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/jedwards1230/deck/internal/model"
@@ -8,10 +9,11 @@ import (
 
 func TestParseFrontmatter(t *testing.T) {
 	tests := []struct {
-		name          string
-		input         string
-		wantFM        model.Frontmatter
-		wantRemaining string
+		name       string
+		input      string
+		wantFM     model.Frontmatter
+		wantSlides []string
+		wantErr    bool
 	}{
 		{
 			name: "valid frontmatter with all fields",
@@ -28,23 +30,23 @@ footer: My Presentation
 				Paging: "Page %d of %d",
 				Footer: "My Presentation",
 			},
-			wantRemaining: "# Hello World",
+			wantSlides: []string{"# Hello World"},
 		},
 		{
-			name:  "content without frontmatter",
-			input: "# Hello World\n\nSome body text.",
+			name:   "content without frontmatter",
+			input:  "# Hello World\n\nSome body text.",
 			wantFM: model.Frontmatter{
 				// All zero values, no defaults applied.
 			},
-			wantRemaining: "# Hello World\n\nSome body text.",
+			wantSlides: []string{"# Hello World\n\nSome body text."},
 		},
 		{
-			name:  "only opening delimiter no closing",
-			input: "---\nauthor: Test\n# Slide content",
+			name:   "only opening delimiter no closing",
+			input:  "---\nauthor: Test\n# Slide content",
 			wantFM: model.Frontmatter{
 				// No closing ---, so frontmatter is not parsed.
 			},
-			wantRemaining: "---\nauthor: Test\n# Slide content",
+			wantSlides: []string{"---\nauthor: Test\n# Slide content"},
 		},
 		{
 			name:  "empty frontmatter block",
@@ -52,7 +54,7 @@ footer: My Presentation
 			wantFM: model.Frontmatter{
 				Paging: "Slide %d / %d", // default applied
 			},
-			wantRemaining: "# Slide content",
+			wantSlides: []string{"# Slide content"},
 		},
 		{
 			name: "default paging when not specified",
@@ -64,7 +66,7 @@ Body`,
 				Author: "Test",
 				Paging: "Slide %d / %d",
 			},
-			wantRemaining: "Body",
+			wantSlides: []string{"Body"},
 		},
 		{
 			name: "custom paging overrides default",
@@ -75,7 +77,7 @@ Content`,
 			wantFM: model.Frontmatter{
 				Paging: "%d / %d",
 			},
-			wantRemaining: "Content",
+			wantSlides: []string{"Content"},
 		},
 		{
 			name: "leading whitespace before frontmatter",
@@ -87,15 +89,33 @@ After`,
 				Author: "Jane",
 				Paging: "Slide %d / %d",
 			},
-			wantRemaining: "After",
+			wantSlides: []string{"After"},
 		},
 		{
-			name:  "empty content",
-			input: "",
+			name:   "closing delimiter inside code fence is ignored",
+			input:  "---\n# Slide\n\n```yaml\na: 1\n---\n```\n",
+			wantFM: model.Frontmatter{
+				// The only --- after the opener is inside a fence, so
+				// there is no frontmatter block.
+			},
+			wantSlides: []string{"---\n# Slide\n\n```yaml\na: 1\n---\n```\n"},
+		},
+		{
+			name:  "closing delimiter must be a whole line",
+			input: "---\n----\n---\nBody",
+			wantFM: model.Frontmatter{
+				Paging: "Slide %d / %d",
+			},
+			wantSlides: []string{"Body"},
+			wantErr:    true, // ---- is not a mapping
+		},
+		{
+			name:   "empty content",
+			input:  "",
 			wantFM: model.Frontmatter{
 				// No frontmatter, no defaults.
 			},
-			wantRemaining: "",
+			wantSlides: nil,
 		},
 		{
 			name: "frontmatter with no remaining content",
@@ -106,13 +126,17 @@ author: Test
 				Author: "Test",
 				Paging: "Slide %d / %d",
 			},
-			wantRemaining: "",
+			wantSlides: nil,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotFM, gotRemaining := ParseFrontmatter(tt.input)
+			pres := ParsePresentation(tt.input)
+			gotFM := pres.Frontmatter
+			if gotErr := slices.Contains(diagCodes(pres), "frontmatter-yaml"); gotErr != tt.wantErr {
+				t.Errorf("diagnostics = %v, want frontmatter-yaml %v", diagCodes(pres), tt.wantErr)
+			}
 
 			if gotFM.Author != tt.wantFM.Author {
 				t.Errorf("Author = %q, want %q", gotFM.Author, tt.wantFM.Author)
@@ -126,8 +150,8 @@ author: Test
 			if gotFM.Footer != tt.wantFM.Footer {
 				t.Errorf("Footer = %q, want %q", gotFM.Footer, tt.wantFM.Footer)
 			}
-			if gotRemaining != tt.wantRemaining {
-				t.Errorf("remaining = %q, want %q", gotRemaining, tt.wantRemaining)
+			if got := slideTexts(tt.input); !slices.Equal(got, tt.wantSlides) {
+				t.Errorf("slides = %q, want %q", got, tt.wantSlides)
 			}
 		})
 	}
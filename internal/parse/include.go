@@ -0,0 +1,166 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// maxIncludeDepth bounds how deeply include directives may nest.
+const maxIncludeDepth = 10
+
+// directiveRegex matches a line that consists of a single HTML comment.
+var directiveRegex = regexp.MustCompile(`^ {0,3}<!--\s*(.*?)\s*-->\s*$`)
+
+// includedFrontmatter is the frontmatter block of an included file.
+type includedFrontmatter struct {
+	file string
+	frontmatterBlock
+}
+
+// expand appends content[offset:], which belongs to file, to the parsed
+// text, splicing in the files named by include directives, the code
+// excerpts named by file directives and the tables built by table
+// directives. Directives must sit on their own line outside code fences
+// and HTML blocks.
+func (p *parser) expand(file, content string, offset, depth int) {
+	var scanner blockScanner
+	copied := offset
+	pos := offset
+	for _, raw := range strings.SplitAfter(content[offset:], "\n") {
+		line := strings.TrimSuffix(raw, "\n")
+		lineStart := pos
+		pos += len(raw)
+
+		inBlock := scanner.inBlock()
+		scanner.literal(line)
+		if inBlock {
+			continue
+		}
+		m := directiveRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		cmd, ok := parseCommand(m[1])
+		if !ok || (cmd.Type != model.CmdInclude && cmd.Type != model.CmdFile && cmd.Type != model.CmdTable) {
+			continue
+		}
+
+		// Replace the directive line, keeping its newline.
+		p.src.write(file, copied, content[copied:lineStart])
+		directive := p.src.fileSpan(file, lineStart, lineStart+len(line))
+		switch cmd.Type {
+		case model.CmdInclude:
+			p.include(file, cmd.Value, directive, depth)
+		case model.CmdFile:
+			p.excerpt(file, cmd.Value, directive)
+		case model.CmdTable:
+			p.table(file, cmd.Value, directive)
+		}
+		copied = lineStart + len(line)
+	}
+	p.src.write(file, copied, content[copied:])
+}
+
+// include splices the file at path, relative to the including file, into
+// the parsed text. Failures are reported at the directive and leave an
+// empty line behind.
+func (p *parser) include(from, path string, directive model.Span, depth int) {
+	if file, content, ok := p.load(from, path, directive, depth); ok {
+		p.splice(file, content, depth)
+	}
+}
+
+// load reads the file at path, relative to the including file, for
+// splicing. Failures are reported at the directive.
+func (p *parser) load(from, path string, directive model.Span, depth int) (string, string, bool) {
+	resolved := path
+	if !filepath.IsAbs(path) {
+		resolved = filepath.Join(p.baseDir(from), path)
+	}
+
+	if depth >= maxIncludeDepth {
+		p.reportSpan(model.SeverityError, "include-depth", directive,
+			"include of %s exceeds the maximum depth of %d", path, maxIncludeDepth)
+		return "", "", false
+	}
+	if slices.Contains(p.including, absPath(resolved)) {
+		p.reportSpan(model.SeverityError, "include-cycle", directive,
+			"include of %s creates a cycle", path)
+		return "", "", false
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "include-missing", directive,
+			"cannot include %s: %v", path, unwrapPathError(err))
+		p.addMissing(resolved)
+		return "", "", false
+	}
+	p.addSource(resolved)
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	p.src.addFile(resolved, content)
+	return resolved, content, true
+}
+
+// splice expands a loaded file into the parsed text, setting its
+// frontmatter aside to be merged with the deck's.
+func (p *parser) splice(file, content string, depth int) {
+	// The included file's trailing newline is dropped so the directive's
+	// own newline ends the spliced text.
+	content = strings.TrimRight(content, "\n")
+	bodyStart := 0
+	if block, ok := splitFrontmatter(content); ok {
+		p.includedFM = append(p.includedFM, includedFrontmatter{file: file, frontmatterBlock: block})
+		bodyStart = min(block.bodyStart, len(content))
+	}
+
+	p.including = append(p.including, absPath(file))
+	p.expand(file, content, bodyStart, depth+1)
+	p.including = p.including[:len(p.including)-1]
+}
+
+// baseDir returns the directory that paths named in from resolve against:
+// the directory containing from, or from itself for a directory deck.
+func (p *parser) baseDir(from string) string {
+	if from == p.root && p.rootIsDir {
+		return from
+	}
+	return filepath.Dir(from)
+}
+
+// addSource records a file the presentation was read from.
+func (p *parser) addSource(file string) {
+	if file != "" && !slices.Contains(p.sources, file) {
+		p.sources = append(p.sources, file)
+	}
+}
+
+// addMissing records a file the presentation names but could not read, so
+// a watcher can reload once it appears.
+func (p *parser) addMissing(file string) {
+	if !slices.Contains(p.missing, file) {
+		p.missing = append(p.missing, file)
+	}
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// unwrapPathError drops the path from an *os.PathError, which the caller
+// already reports in its own words.
+func unwrapPathError(err error) error {
+	if pe, ok := err.(*os.PathError); ok {
+		return pe.Err
+	}
+	return err
+}
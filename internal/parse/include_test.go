@@ -0,0 +1,183 @@
+package parse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// writeFiles creates files under dir, keyed by slash-separated relative path.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func parseFile(t *testing.T, path string) *model.Presentation {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Parse(string(data), Options{Path: path})
+}
+
+func diagCodes(p *model.Presentation) []string {
+	var codes []string
+	for _, d := range p.Diagnostics {
+		codes = append(codes, d.Code)
+	}
+	return codes
+}
+
+// includeChain returns deck.md including a1.md, which includes a2.md, and
+// so on down to an{n}.md.
+func includeChain(n int) map[string]string {
+	files := map[string]string{"deck.md": "<!-- include: a1.md -->\n"}
+	for i := 1; i < n; i++ {
+		files[fmt.Sprintf("a%d.md", i)] = fmt.Sprintf("<!-- include: a%d.md -->\n", i+1)
+	}
+	files[fmt.Sprintf("a%d.md", n)] = "# Deep\n"
+	return files
+}
+
+func TestParseInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"deck.md":         "# Intro\n---\n<!-- include: parts/middle.md -->\n---\n# Outro\n",
+		"parts/middle.md": "# Middle A\n---\n<!-- include: ../shared/code.md -->\n",
+		"shared/code.md":  "# Code\n\n```md\n<!-- include: nope.md -->\n```\n",
+	})
+	root := filepath.Join(dir, "deck.md")
+	pres := parseFile(t, root)
+
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	var titles []string
+	for _, s := range pres.Slides {
+		titles = append(titles, strings.SplitN(strings.TrimSpace(s.Chunks[0].Content), "\n", 2)[0])
+	}
+	want := []string{"# Intro", "# Middle A", "# Code", "# Outro"}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Errorf("slide titles = %q, want %q", titles, want)
+	}
+
+	// The directive inside the fence would report include-missing if it
+	// were expanded.
+	code := pres.Slides[2]
+	wantFile := filepath.Join(dir, "shared", "code.md")
+	if code.Span.File != wantFile {
+		t.Errorf("code slide file = %q, want %q", code.Span.File, wantFile)
+	}
+	if code.Span.StartLine != 1 {
+		t.Errorf("code slide line = %d, want 1", code.Span.StartLine)
+	}
+	if cb := code.CodeBlocks[0].Span; cb.StartLine != 3 || cb.EndLine != 5 {
+		t.Errorf("code block lines = %d-%d, want 3-5", cb.StartLine, cb.EndLine)
+	}
+	if f := pres.Slides[3].Span.File; f != root {
+		t.Errorf("outro file = %q, want %q", f, root)
+	}
+	if got := pres.Slides[3].Span.StartLine; got != 5 {
+		t.Errorf("outro line = %d, want 5", got)
+	}
+	if len(pres.Sources) != 3 || pres.Sources[0] != root {
+		t.Errorf("sources = %q", pres.Sources)
+	}
+}
+
+func TestParseIncludeFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"deck.md": "---\nauthor: Root\n---\n<!-- include: a.md -->\n",
+		"a.md":    "---\nauthor: A\nfooter: From A\n---\n# A\n",
+	})
+	pres := parseFile(t, filepath.Join(dir, "deck.md"))
+
+	fm := pres.Frontmatter
+	if fm.Author != "Root" || fm.Footer != "From A" || fm.Paging != "Slide %d / %d" {
+		t.Errorf("frontmatter = %+v, want author Root, footer From A and default paging", fm)
+	}
+	if len(pres.Slides) != 1 || strings.TrimSpace(pres.Slides[0].Chunks[0].Content) != "# A" {
+		t.Errorf("slides = %+v", pres.Slides)
+	}
+}
+
+func TestParseIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantCode string
+		wantLine int
+		wantFile string
+	}{
+		{
+			name:     "missing file",
+			files:    map[string]string{"deck.md": "# One\n\n<!-- include: gone.md -->\n"},
+			wantCode: "include-missing",
+			wantLine: 3,
+			wantFile: "deck.md",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"deck.md": "# One\n---\n<!-- include: a.md -->\n",
+				"a.md":    "# A\n---\n<!-- include: deck.md -->\n",
+			},
+			wantCode: "include-cycle",
+			wantLine: 3,
+			wantFile: "a.md",
+		},
+		{
+			name:     "too deep",
+			files:    includeChain(11),
+			wantCode: "include-depth",
+			wantLine: 1,
+			wantFile: "a10.md",
+		},
+		{
+			name:     "inline directive",
+			files:    map[string]string{"deck.md": "# One\n\nSee <!-- include: a.md --> here\n"},
+			wantCode: "inline-include",
+			wantLine: 3,
+			wantFile: "deck.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			pres := parseFile(t, filepath.Join(dir, "deck.md"))
+
+			if len(pres.Diagnostics) != 1 {
+				t.Fatalf("diagnostics = %v, want one %s", diagCodes(pres), tt.wantCode)
+			}
+			d := pres.Diagnostics[0]
+			if d.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", d.Code, tt.wantCode)
+			}
+			if d.Span.StartLine != tt.wantLine {
+				t.Errorf("line = %d, want %d", d.Span.StartLine, tt.wantLine)
+			}
+			if filepath.Base(d.Span.File) != tt.wantFile {
+				t.Errorf("file = %q, want %s", d.Span.File, tt.wantFile)
+			}
+			if tt.wantCode == "include-missing" && len(pres.Missing) != 1 {
+				t.Errorf("missing = %q, want the included file", pres.Missing)
+			}
+		})
+	}
+}
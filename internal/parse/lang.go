@@ -0,0 +1,99 @@
+package parse
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// langTagRegex matches a language tag such as en, es or pt-BR.
+var langTagRegex = regexp.MustCompile(`^[A-Za-z]{2,8}(?:-[A-Za-z0-9]{1,8})*$`)
+
+// isLangCommand reports whether the inner text of a comment opens or
+// closes a lang region.
+func isLangCommand(inner string) bool {
+	cmd, ok := parseCommand(inner)
+	return ok && (cmd.Type == model.CmdLang || cmd.Type == model.CmdEndLang)
+}
+
+// addLanguage records a language the deck is written in.
+func (p *parser) addLanguage(lang string) {
+	if lang != "" && !slices.Contains(p.languages, lang) {
+		p.languages = append(p.languages, lang)
+	}
+}
+
+// firstLanguage returns the language of the first well-formed lang comment
+// in text, outside code, or "" when there is none.
+func firstLanguage(text string) string {
+	cmds, _ := extractCommands(text, isLangCommand)
+	for _, cmd := range cmds {
+		if cmd.Command.Type == model.CmdLang && langTagRegex.MatchString(cmd.Command.Value) {
+			return cmd.Command.Value
+		}
+	}
+	return ""
+}
+
+// filterLang removes the lang and end_lang comments from raw, outside
+// code, along with the regions written in languages other than the
+// selected one. With a notes language, the speaker notes of each region
+// are kept or dropped by that language instead. Content outside lang
+// regions is shared by every language. Like filterOnly, it cuts the text
+// from the parsed text too.
+func (p *parser) filterLang(raw string, base int) string {
+	regions := p.regions(raw, base, model.CmdLang, model.CmdEndLang, "lang")
+	c := cutter{raw: raw}
+	for _, r := range regions {
+		lang := r.open.Command.Value
+		if !langTagRegex.MatchString(lang) {
+			p.report(model.SeverityWarning, "invalid-command", base+r.open.Start, base+r.open.End,
+				"lang comment: %q is not a language tag such as en or pt-BR", lang)
+			continue
+		}
+		p.addLanguage(lang)
+
+		show := p.lang == "" || lang == p.lang
+		notes := show
+		if p.notesLang != "" {
+			notes = lang == p.notesLang
+		}
+		switch {
+		case show && notes:
+		case !show && !notes:
+			c.cut(r.inner[0], r.inner[1])
+		case show:
+			for _, n := range noteRanges(raw, r.inner) {
+				c.cut(n[0], n[1])
+			}
+		default:
+			last := r.inner[0]
+			for _, n := range noteRanges(raw, r.inner) {
+				c.cut(last, n[0])
+				last = n[1]
+			}
+			c.cut(last, r.inner[1])
+		}
+	}
+	c.cutComments(isLangCommand)
+	return c.apply(p.src, base)
+}
+
+// noteRanges returns the ranges of the speaker note comments and notes
+// blocks within span of raw, in order.
+func noteRanges(raw string, span [2]int) [][2]int {
+	text := raw[span[0]:span[1]]
+	var ranges [][2]int
+	cmds, _ := extractCommands(text, isNoteCommand)
+	for _, cmd := range cmds {
+		ranges = append(ranges, [2]int{span[0] + cmd.Start, span[0] + cmd.End})
+	}
+	_, divs := extractNoteDivs(text)
+	for _, div := range divs {
+		ranges = append(ranges, [2]int{span[0] + div.start, span[0] + div.end})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	return ranges
+}
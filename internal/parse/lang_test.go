@@ -0,0 +1,165 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jedwards1230/deck/internal/render"
+)
+
+const langDeck = `---
+lang: en
+---
+<!-- lang: en -->
+# Welcome
+<!-- speaker_note: Smile. -->
+<!-- end_lang -->
+<!-- lang: es -->
+# Bienvenidos
+<!-- speaker_note: Sonríe. -->
+<!-- end_lang -->
+
+Shared by both.
+---
+<!-- lang: en -->
+- one
+<!-- pause -->
+- two
+<!-- end_lang -->
+<!-- lang: es -->
+- uno
+<!-- pause -->
+- dos
+<!-- end_lang -->`
+
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		wantTitle string
+		wantNotes string
+		wantList  string
+	}{
+		{"frontmatter lang", Options{}, "Welcome", "Smile.", "- one"},
+		{"selected lang", Options{Lang: "es"}, "Bienvenidos", "Sonríe.", "- uno"},
+		{"notes in the presenter's language", Options{Lang: "es", NotesLang: "en"}, "Bienvenidos", "Smile.", "- uno"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := Parse(langDeck, tt.opts)
+			if len(pres.Diagnostics) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+			}
+			if got := strings.Join(pres.Languages, ","); got != "en,es" {
+				t.Errorf("languages = %s, want en,es", got)
+			}
+
+			first := pres.Slides[0]
+			if first.Title() != tt.wantTitle {
+				t.Errorf("title = %q, want %q", first.Title(), tt.wantTitle)
+			}
+			if got := strings.Join(first.SpeakerNotes, "|"); got != tt.wantNotes {
+				t.Errorf("notes = %q, want %q", got, tt.wantNotes)
+			}
+			content := first.VisibleContent(0)
+			if !strings.Contains(content, "Shared by both.") || strings.Contains(content, "lang") {
+				t.Errorf("content = %q, want the shared text and no lang comments", content)
+			}
+
+			list := pres.Slides[1]
+			if len(list.Chunks) != 2 || !strings.Contains(list.VisibleContent(1), tt.wantList) {
+				t.Errorf("list = %d chunks %q, want 2 with %q", len(list.Chunks), list.VisibleContent(1), tt.wantList)
+			}
+		})
+	}
+}
+
+func TestParseLangWithoutSelection(t *testing.T) {
+	deck := strings.TrimPrefix(langDeck, "---\nlang: en\n---\n")
+	tests := []struct {
+		name      string
+		opts      Options
+		wantNotes string
+	}{
+		{"first declared language", Options{}, "Smile."},
+		{"notes language alone", Options{NotesLang: "es"}, "Sonríe."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := Parse(deck, tt.opts)
+			if pres.Frontmatter.Lang != "en" {
+				t.Errorf("lang = %q, want en", pres.Frontmatter.Lang)
+			}
+			content := pres.Slides[0].VisibleContent(0)
+			if !strings.Contains(content, "Welcome") || strings.Contains(content, "Bienvenidos") {
+				t.Errorf("content = %q, want only the first language", content)
+			}
+			if got := strings.Join(pres.Slides[0].SpeakerNotes, "|"); got != tt.wantNotes {
+				t.Errorf("notes = %q, want %q", got, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestRenderLangRegions(t *testing.T) {
+	input := "# Deck\n\n<!-- lang: en -->**Welcome**<!-- end_lang --><!-- lang: es -->**Bienvenidos**<!-- end_lang -->\n\n" +
+		"- one\n<!-- lang: en -->\n- two\n<!-- end_lang -->\n- three"
+
+	tests := []struct {
+		lang  string
+		title string
+		items []string
+	}{
+		{"en", "Welcome", []string{"one", "two", "three"}},
+		{"es", "Bienvenidos", []string{"one", "three"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			slide := Parse(input, Options{Lang: tt.lang}).Slides[0]
+			if content := slide.VisibleContent(0); !strings.Contains(content, "- one\n- ") {
+				t.Errorf("content = %q, want the list kept tight", content)
+			}
+			out, err := render.RenderSlide(slide, 0, 40, render.NewRendererCache(true))
+			if err != nil {
+				t.Fatalf("RenderSlide() error: %v", err)
+			}
+
+			// A region indented into a code block would show its markup.
+			var lines []string
+			for _, line := range strings.Split(ansi.Strip(out), "\n") {
+				lines = append(lines, strings.TrimSpace(line))
+			}
+			got := strings.Join(lines, "|")
+			items := "• " + strings.Join(tt.items, "|• ")
+			if !strings.Contains(got, "|"+tt.title+"|") || !strings.Contains(got, items) || strings.Contains(got, "*") {
+				t.Errorf("rendered %q, want %s and the items %s", got, tt.title, items)
+			}
+		})
+	}
+}
+
+func TestParseLangDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantLine int
+	}{
+		{"end without lang", "# One\n<!-- end_lang -->", "unmatched-lang", 2},
+		{"unclosed region", "# One\n<!-- lang: es -->\nHola", "unmatched-lang", 2},
+		{"invalid tag", "# One\n<!-- lang: spanish please -->\nHola\n<!-- end_lang -->", "invalid-command", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := Parse(tt.input, Options{Lang: "en"})
+			if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != tt.wantCode ||
+				pres.Diagnostics[0].Span.StartLine != tt.wantLine {
+				t.Fatalf("diagnostics = %v, want one %s on line %d", pres.Diagnostics, tt.wantCode, tt.wantLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package parse
+
+import (
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// layoutCommands are the command types that shape a slide's grid.
+var layoutCommands = map[model.CommandType]bool{
+	model.CmdColumnLayout: true,
+	model.CmdRow:          true,
+	model.CmdColumn:       true,
+	model.CmdGrid:         true,
+	model.CmdEndGrid:      true,
+	model.CmdResetLayout:  true,
+	model.CmdAlign:        true,
+	model.CmdVAlign:       true,
+}
+
+// gridBuilder assembles a slide's grid from its layout commands, routing
+// the content between them into cells.
+type gridBuilder struct {
+	p      *parser
+	raw    string
+	base   int
+	pauses [][]int // pause markers in raw, which step the content
+
+	top      *model.Grid
+	frames   []gridFrame // open grids, innermost last
+	leaves   []gridLeaf
+	current  int      // leaf receiving content, or -1
+	dropping bool     // content is dropped after a reported bad column
+	preamble gridLeaf // content before the first row
+
+	align, valign string // alignment set outside any cell, for the slide
+}
+
+// gridFrame is an open grid and its selected cell.
+type gridFrame struct {
+	grid     *model.Grid
+	row, col int // -1 when none is selected
+}
+
+// gridLeaf accumulates the content of one cell.
+type gridLeaf struct {
+	content    string
+	steps      []string // content added at each reveal step
+	start, end int      // range of the parsed text it was taken from
+	implicit   bool     // full-width row added after reset_layout
+}
+
+func (l *gridLeaf) add(content string, step, start, end int) {
+	if l.content == "" && l.end == 0 {
+		l.start = start
+	}
+	l.content += content
+	for len(l.steps) <= step {
+		l.steps = append(l.steps, "")
+	}
+	l.steps[step] += content
+	l.end = end
+}
+
+// extractGrid builds the slide's grid from layout commands outside code:
+// column_layout and row start rows, column selects a cell of the current
+// row, grid and end_grid open and close a grid nested in the selected cell,
+// and reset_layout returns to full width. Content before the first row and
+// after reset_layout becomes a full-width row. It leaves the grid nil on a
+// slide without rows.
+//
+// align and valign set the alignment of the selected cell, or of the whole
+// slide when no cell is selected.
+//
+// Pauses anywhere on the slide step the reveal in source order, so each
+// chunk of the slide records what it adds to every cell.
+func (p *parser) extractGrid(slide *model.Slide, raw string, base int, codeRanges [][2]int, pauses [][]int) {
+	b := &gridBuilder{p: p, raw: raw, base: base, pauses: pauses, current: -1}
+
+	prev := 0
+	for _, loc := range commentRegex.FindAllStringSubmatchIndex(raw, -1) {
+		if inRanges(loc[0], codeRanges) {
+			continue
+		}
+		cmd, ok := parseCommand(strings.TrimSpace(raw[loc[2]:loc[3]]))
+		if !ok || !layoutCommands[cmd.Type] {
+			continue
+		}
+		b.addSection(prev, loc[0])
+		b.apply(cmd, loc[0], loc[1])
+		prev = loc[1]
+	}
+	b.addSection(prev, len(raw))
+
+	if b.align != "" {
+		slide.Align = b.align
+	}
+	if b.valign != "" {
+		slide.VAlign = b.valign
+	}
+	b.finish(slide)
+}
+
+// addSection routes raw[start:end] to the current cell, piece by piece
+// between the pauses it contains.
+func (b *gridBuilder) addSection(start, end int) {
+	step := 0
+	for _, pause := range b.pauses {
+		if pause[1] <= start {
+			step++
+			continue
+		}
+		if pause[0] >= end {
+			break
+		}
+		b.addPiece(start, pause[0], step)
+		start = pause[1]
+		step++
+	}
+	b.addPiece(start, end, step)
+}
+
+func (b *gridBuilder) addPiece(start, end, step int) {
+	if start >= end {
+		return
+	}
+	_, cleaned := extractNonPauseCommands(b.raw[start:end])
+
+	switch {
+	case b.current >= 0:
+		b.leaves[b.current].add(cleaned, step, b.base+start, b.base+end)
+	case b.top == nil:
+		b.preamble.add(cleaned, step, b.base+start, b.base+end)
+	case !b.dropping && strings.TrimSpace(cleaned) != "":
+		s, e := trimRange(b.raw, start, end)
+		b.p.report(model.SeverityWarning, "content-outside-cell", b.base+s, b.base+e,
+			"content is not in a cell of the layout; add a column marker before it")
+	}
+}
+
+func (b *gridBuilder) apply(cmd model.Command, start, end int) {
+	report := func(sev model.Severity, code, format string, args ...any) {
+		s, e := trimRange(b.raw, start, end)
+		b.p.report(sev, code, b.base+s, b.base+e, format, args...)
+	}
+
+	b.dropping = false
+	switch cmd.Type {
+	case model.CmdColumnLayout, model.CmdRow:
+		if b.top == nil {
+			b.top = &model.Grid{}
+		}
+		if len(b.frames) == 0 {
+			b.frames = []gridFrame{{grid: b.top, row: -1, col: -1}}
+		}
+		b.addRow(b.inner(), cmd.Ratios, false)
+
+	case model.CmdColumn:
+		f := b.inner()
+		if f == nil || f.row < 0 {
+			report(model.SeverityWarning, "column-without-layout",
+				"column marker without a column_layout or row; its content is shown full-width")
+			return
+		}
+		row := f.grid.Rows[f.row]
+		if cmd.Column < 0 || cmd.Column >= len(row.Cells) {
+			report(model.SeverityError, "column-out-of-range",
+				"column %d is outside the %d-column layout; its content is dropped", cmd.Column, len(row.Cells))
+			f.col, b.current, b.dropping = -1, -1, true
+			return
+		}
+		f.col = cmd.Column
+		b.current = -1
+		if cell := row.Cells[cmd.Column]; cell.Grid == nil {
+			b.current = cell.Leaf
+		}
+
+	case model.CmdGrid:
+		f := b.inner()
+		if f == nil || f.col < 0 {
+			report(model.SeverityWarning, "invalid-layout", "grid must follow a column marker; it was ignored")
+			return
+		}
+		cell := &f.grid.Rows[f.row].Cells[f.col]
+		if cell.Grid == nil {
+			if strings.TrimSpace(b.leaves[cell.Leaf].content) != "" {
+				report(model.SeverityWarning, "invalid-layout",
+					"grid must come before any content in its cell; it was ignored")
+				return
+			}
+			cell.Grid = &model.Grid{}
+		}
+		b.frames = append(b.frames, gridFrame{grid: cell.Grid, row: len(cell.Grid.Rows) - 1, col: -1})
+		b.current = -1
+
+	case model.CmdEndGrid:
+		if len(b.frames) < 2 {
+			report(model.SeverityWarning, "invalid-layout", "end_grid without a matching grid; it was ignored")
+			return
+		}
+		b.frames = b.frames[:len(b.frames)-1]
+		b.current = -1
+
+	case model.CmdAlign, model.CmdVAlign:
+		align, valign := &b.align, &b.valign
+		if f := b.inner(); f != nil && f.col >= 0 {
+			cell := &f.grid.Rows[f.row].Cells[f.col]
+			align, valign = &cell.Align, &cell.VAlign
+		}
+		if cmd.Type == model.CmdAlign {
+			*align = cmd.Value
+		} else {
+			*valign = cmd.Value
+		}
+
+	case model.CmdResetLayout:
+		if b.top == nil {
+			return
+		}
+		b.frames = nil
+		b.addRow(&gridFrame{grid: b.top}, []int{1}, true)
+	}
+}
+
+// inner returns the innermost open grid, or nil.
+func (b *gridBuilder) inner() *gridFrame {
+	if len(b.frames) == 0 {
+		return nil
+	}
+	return &b.frames[len(b.frames)-1]
+}
+
+// addRow appends a row to f's grid and selects it. A one-cell row selects
+// its cell so content can follow without a column marker.
+func (b *gridBuilder) addRow(f *gridFrame, ratios []int, implicit bool) {
+	row := model.GridRow{Ratios: ratios, Cells: make([]model.GridCell, len(ratios))}
+	for i := range row.Cells {
+		row.Cells[i].Leaf = len(b.leaves)
+		b.leaves = append(b.leaves, gridLeaf{implicit: implicit})
+	}
+	f.grid.Rows = append(f.grid.Rows, row)
+	f.row, f.col, b.current = len(f.grid.Rows)-1, -1, -1
+	if len(ratios) == 1 {
+		f.col, b.current = 0, row.Cells[0].Leaf
+	}
+}
+
+// finish adds the preamble row, drops empty rows left by reset_layout,
+// numbers the cells in reading order and stores the grid on the slide.
+func (b *gridBuilder) finish(slide *model.Slide) {
+	if b.top == nil {
+		return
+	}
+
+	if strings.TrimSpace(b.preamble.content) != "" {
+		row := model.GridRow{Ratios: []int{1}, Cells: []model.GridCell{{Leaf: len(b.leaves)}}}
+		b.leaves = append(b.leaves, b.preamble)
+		b.top.Rows = append([]model.GridRow{row}, b.top.Rows...)
+	}
+
+	rows := b.top.Rows[:0]
+	for _, row := range b.top.Rows {
+		if len(row.Cells) == 1 && row.Cells[0].Grid == nil {
+			leaf := b.leaves[row.Cells[0].Leaf]
+			if leaf.implicit && strings.TrimSpace(leaf.content) == "" {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	b.top.Rows = rows
+
+	var columns []string
+	var spans []model.Span
+	var leaves []gridLeaf
+	renumber(b.top, func(leaf int) int {
+		l := b.leaves[leaf]
+		leaves = append(leaves, l)
+		columns = append(columns, l.content)
+		var span model.Span
+		if l.end > 0 {
+			span = b.p.src.span(l.start, l.end)
+		}
+		spans = append(spans, span)
+		return len(columns) - 1
+	})
+	slide.Grid, slide.Columns, slide.ColumnSpans = b.top, columns, spans
+
+	for step := range slide.Chunks {
+		cells := make([]string, len(leaves))
+		for i, l := range leaves {
+			if step < len(l.steps) {
+				cells[i] = l.steps[step]
+			}
+		}
+		slide.Chunks[step].Cells = cells
+	}
+}
+
+// renumber replaces every leaf index in g, in reading order, with the
+// result of next.
+func renumber(g *model.Grid, next func(leaf int) int) {
+	for r := range g.Rows {
+		for c := range g.Rows[r].Cells {
+			cell := &g.Rows[r].Cells[c]
+			if cell.Grid != nil {
+				renumber(cell.Grid, next)
+			} else {
+				cell.Leaf = next(cell.Leaf)
+			}
+		}
+	}
+}
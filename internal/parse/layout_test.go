@@ -0,0 +1,324 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGrid(t *testing.T) {
+	input := `# Architecture
+
+<!-- row: [2, 1] -->
+<!-- column: 0 -->
+Main diagram
+<!-- column: 1 -->
+<!-- grid -->
+<!-- row -->
+Top note
+<!-- row -->
+Bottom note
+<!-- end_grid -->
+<!-- row -->
+` + "```sh\nmake deploy\n```" + `
+<!-- reset_layout -->
+Closing line`
+
+	p := ParsePresentation(input)
+	if len(p.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+	s := p.Slides[0]
+	if s.Grid == nil {
+		t.Fatal("slide should have a grid")
+	}
+
+	want := []string{"# Architecture", "Main diagram", "Top note", "Bottom note", "make deploy", "Closing line"}
+	if len(s.Columns) != len(want) {
+		t.Fatalf("cells = %q, want %d cells", s.Columns, len(want))
+	}
+	for i, w := range want {
+		if !strings.Contains(s.Columns[i], w) {
+			t.Errorf("cell %d = %q, want it to contain %q", i, s.Columns[i], w)
+		}
+	}
+
+	rows := s.Grid.Rows
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want header, split, code and closing", len(rows))
+	}
+	if !intSliceEqual(rows[1].Ratios, []int{2, 1}) {
+		t.Errorf("row 1 ratios = %v, want [2 1]", rows[1].Ratios)
+	}
+	nested := rows[1].Cells[1].Grid
+	if nested == nil || len(nested.Rows) != 2 {
+		t.Fatalf("row 1 cell 1 should hold a two-row grid, got %+v", rows[1].Cells[1])
+	}
+	if nested.Rows[0].Cells[0].Leaf != 2 || nested.Rows[1].Cells[0].Leaf != 3 {
+		t.Errorf("nested cells = %+v, want cells 2 and 3 in reading order", nested.Rows)
+	}
+	if got := s.ColumnSpans[2].StartLine; got != 8 {
+		t.Errorf("nested cell starts on line %d, want 8", got)
+	}
+}
+
+func TestParseGridColumnLayout(t *testing.T) {
+	p := ParsePresentation("# Compare\n<!-- column_layout: [1, 1] -->\n<!-- column: 0 -->\nA\n<!-- column: 1 -->\nB\n<!-- reset_layout -->\n")
+	s := p.Slides[0]
+
+	if s.Grid == nil {
+		t.Fatal("slide should have a grid")
+	}
+	if len(s.Grid.Rows) != 2 {
+		t.Fatalf("rows = %d, want the heading row and the columns (no empty row after reset)", len(s.Grid.Rows))
+	}
+	if got := strings.TrimSpace(s.Columns[0]); got != "# Compare" {
+		t.Errorf("header cell = %q, want the heading above the layout", got)
+	}
+}
+
+func TestParseGridDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+	}{
+		{
+			name:     "content before a column marker",
+			input:    "<!-- row: [1, 1] -->\nStray\n<!-- column: 0 -->\nA",
+			wantCode: "content-outside-cell",
+		},
+		{
+			name:     "grid without a cell",
+			input:    "<!-- grid -->\nA",
+			wantCode: "invalid-layout",
+		},
+		{
+			name:     "grid after content",
+			input:    "<!-- row -->\nText\n<!-- grid -->\n<!-- row -->\nB",
+			wantCode: "invalid-layout",
+		},
+		{
+			name:     "unmatched end_grid",
+			input:    "<!-- row -->\nA\n<!-- end_grid -->",
+			wantCode: "invalid-layout",
+		},
+		{
+			name:     "column in a one-cell row",
+			input:    "<!-- row -->\n<!-- column: 1 -->\nA",
+			wantCode: "column-out-of-range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ParsePresentation(tt.input)
+			if len(p.Diagnostics) != 1 || p.Diagnostics[0].Code != tt.wantCode {
+				t.Errorf("diagnostics = %v, want one %s", diagCodes(p), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestParseGridRegions(t *testing.T) {
+	input := `# Title
+<!-- column_layout: [1, 1] -->
+<!-- column: 0 -->
+A
+<!-- column: 1 -->
+B
+<!-- reset_layout -->
+Middle takeaway
+<!-- column_layout: [1, 2] -->
+<!-- column: 0 -->
+C
+<!-- column: 1 -->
+D
+<!-- reset_layout -->
+Final takeaway`
+
+	p := ParsePresentation(input)
+	if len(p.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+	s := p.Slides[0]
+
+	var ratios [][]int
+	for _, row := range s.Grid.Rows {
+		ratios = append(ratios, row.Ratios)
+	}
+	wantRatios := [][]int{{1}, {1, 1}, {1}, {1, 2}, {1}}
+	if len(ratios) != len(wantRatios) {
+		t.Fatalf("rows = %v, want %v", ratios, wantRatios)
+	}
+	for i := range wantRatios {
+		if !intSliceEqual(ratios[i], wantRatios[i]) {
+			t.Errorf("row %d ratios = %v, want %v", i, ratios[i], wantRatios[i])
+		}
+	}
+
+	want := []string{"# Title", "A", "B", "Middle takeaway", "C", "D", "Final takeaway"}
+	if len(s.Columns) != len(want) {
+		t.Fatalf("cells = %q, want %q", s.Columns, want)
+	}
+	for i, w := range want {
+		if got := strings.TrimSpace(s.Columns[i]); got != w {
+			t.Errorf("cell %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestParseNoGrid(t *testing.T) {
+	p := ParsePresentation("# Plain\n<!-- reset_layout -->\ntext")
+	if s := p.Slides[0]; s.Grid != nil || len(s.Columns) != 0 {
+		t.Errorf("grid = %+v, columns = %q; want none", s.Grid, s.Columns)
+	}
+}
+
+func TestParseGridPauses(t *testing.T) {
+	input := `# Steps
+<!-- column_layout: [1, 1] -->
+<!-- column: 0 -->
+Left bullet
+<!-- pause -->
+<!-- column: 1 -->
+Right diagram
+<!-- pause -->
+<!-- column: 0 -->
+Left conclusion
+<!-- reset_layout -->`
+
+	s := ParsePresentation(input).Slides[0]
+	if len(s.Chunks) != 3 {
+		t.Fatalf("chunks = %d, want 3", len(s.Chunks))
+	}
+
+	// Cells: heading, left, right.
+	want := [][]string{
+		{"# Steps", "Left bullet", ""},
+		{"# Steps", "Left bullet", "Right diagram"},
+		{"# Steps", "Left bullet\n\nLeft conclusion", "Right diagram"},
+	}
+	for step, cells := range want {
+		got := s.VisibleColumns(step)
+		if len(got) != len(cells) {
+			t.Fatalf("step %d: cells = %q, want %d cells", step, got, len(cells))
+		}
+		for i, w := range cells {
+			if g := strings.Join(strings.Fields(got[i]), " "); g != strings.Join(strings.Fields(w), " ") {
+				t.Errorf("step %d cell %d = %q, want %q", step, i, got[i], w)
+			}
+		}
+	}
+	if strings.Contains(s.Columns[1], "pause") {
+		t.Errorf("cell content kept the pause marker: %q", s.Columns[1])
+	}
+}
+
+func TestParseAlign(t *testing.T) {
+	input := `---
+align: center
+---
+# Default
+
+Text
+---
+<!-- valign: middle -->
+<!-- align: right -->
+# Explicit
+---
+<!-- slide: {class: centered} -->
+# Centered
+---
+# Cells
+<!-- column_layout: [1, 1] -->
+<!-- column: 0 -->
+<!-- align: right -->
+A
+<!-- column: 1 -->
+<!-- valign: bottom -->
+B
+<!-- reset_layout -->
+<!-- align: left -->
+Takeaway`
+
+	p := ParsePresentation(input)
+	if len(p.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+
+	tests := []struct {
+		slide         int
+		align, valign string
+	}{
+		{0, "center", ""},
+		{1, "right", "middle"},
+		{2, "center", "middle"},
+		{3, "left", ""},
+	}
+	for _, tt := range tests {
+		s := p.Slides[tt.slide]
+		if s.Align != tt.align || s.VAlign != tt.valign {
+			t.Errorf("slide %d align = %q/%q, want %q/%q", tt.slide, s.Align, s.VAlign, tt.align, tt.valign)
+		}
+	}
+
+	cells := p.Slides[3].Grid.Rows[1].Cells
+	if cells[0].Align != "right" || cells[0].VAlign != "" {
+		t.Errorf("cell 0 align = %q/%q, want right/top", cells[0].Align, cells[0].VAlign)
+	}
+	if cells[1].Align != "" || cells[1].VAlign != "bottom" {
+		t.Errorf("cell 1 align = %q/%q, want left/bottom", cells[1].Align, cells[1].VAlign)
+	}
+}
+
+func TestParseAlignTitleSlides(t *testing.T) {
+	input := `# Part 2
+
+## Rolling it out
+---
+# Agenda
+
+- One
+---
+<!-- section: Demo -->
+# Demo
+<!-- speaker_note: Breathe. -->
+---
+<!-- align: left -->
+# Left title`
+
+	tests := []struct {
+		name          string
+		frontmatter   string
+		slide         int
+		align, valign string
+	}{
+		{"title slide", "", 0, "center", "middle"},
+		{"content slide", "", 1, "", ""},
+		{"section slide", "", 2, "center", "middle"},
+		{"align command", "", 3, "left", "middle"},
+		{"frontmatter default", "---\nalign: right\n---\n", 0, "right", "middle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ParsePresentation(tt.frontmatter + input).Slides[tt.slide]
+			if s.Align != tt.align || s.VAlign != tt.valign {
+				t.Errorf("align = %q/%q, want %q/%q", s.Align, s.VAlign, tt.align, tt.valign)
+			}
+		})
+	}
+}
+
+func TestParseAlignErrors(t *testing.T) {
+	p := ParsePresentation("---\nvalign: center\n---\n<!-- align: middle -->\n# Slide\n\nText")
+	codes := diagCodes(p)
+	if len(codes) != 2 || codes[0] != "frontmatter-value" || codes[1] != "invalid-command" {
+		t.Fatalf("diagnostics = %v, want frontmatter-value and invalid-command", codes)
+	}
+	if line := p.Diagnostics[0].Span.StartLine; line != 2 {
+		t.Errorf("frontmatter-value on line %d, want 2", line)
+	}
+	if s := p.Slides[0]; s.Align != "" || s.VAlign != "" {
+		t.Errorf("align = %q/%q, want invalid values ignored", s.Align, s.VAlign)
+	}
+}
@@ -0,0 +1,69 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// linkRegex matches an internal markdown link, [text](#target).
+var linkRegex = regexp.MustCompile(`\[([^\]\n]*)\]\(#([^)\s]+)\)`)
+
+// extractAnchors returns the anchors of the headings in raw, outside
+// literal blocks. A slug already used earlier in the deck gets a -1, -2, ...
+// suffix, as GitHub does.
+func (p *parser) extractAnchors(raw string) []string {
+	var anchors []string
+	var scanner blockScanner
+	for _, line := range strings.Split(raw, "\n") {
+		if scanner.literal(line) {
+			continue
+		}
+		text, ok := model.HeadingText(line)
+		if !ok {
+			continue
+		}
+		slug := model.Slug(expandVars(text, p.vars))
+		if slug == "" {
+			continue
+		}
+		if n := p.anchors[slug]; n > 0 {
+			p.anchors[slug]++
+			slug += "-" + strconv.Itoa(n)
+		}
+		p.anchors[slug]++
+		anchors = append(anchors, slug)
+	}
+	return anchors
+}
+
+// extractLinks returns the internal links in raw outside code.
+func (p *parser) extractLinks(raw string, base int, codeRanges [][2]int) []model.Link {
+	spans := inlineCodeSpans(raw)
+	var links []model.Link
+	for _, loc := range linkRegex.FindAllStringSubmatchIndex(raw, -1) {
+		if inRanges(loc[0], codeRanges) || inRanges(loc[0], spans) {
+			continue
+		}
+		links = append(links, model.Link{
+			Text:   expandVars(raw[loc[2]:loc[3]], p.vars),
+			Target: raw[loc[4]:loc[5]],
+			Span:   p.src.span(base+loc[0], base+loc[1]),
+		})
+	}
+	return links
+}
+
+// checkLinks reports links whose target matches no slide id or heading.
+func (p *parser) checkLinks(slides []model.Slide) {
+	for _, slide := range slides {
+		for _, link := range slide.Links {
+			if model.AnchorIndex(slides, link.Target) < 0 {
+				p.reportSpan(model.SeverityWarning, "broken-link", link.Span,
+					"link target #%s matches no slide id or heading", link.Target)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAnchorsAndLinks(t *testing.T) {
+	input := `# Overview
+
+See [the demo](#live-demo) or [setup](#setup).
+
+` + "```md\n# Not A Heading\n[skipped](#nowhere)\n```" + `
+---
+<!-- slide: {id: setup} -->
+# Overview
+## Live Demo
+---
+Back to [the start](#overview) and ` + "`[code](#nowhere)`" + ` and [gone](#missing)`
+
+	p := ParsePresentation(input)
+
+	anchors := [][]string{{"overview"}, {"overview-1", "live-demo"}, nil}
+	for i, want := range anchors {
+		if got := p.Slides[i].Anchors; strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("slide %d anchors = %q, want %q", i, got, want)
+		}
+	}
+
+	links := p.Slides[0].Links
+	if len(links) != 2 {
+		t.Fatalf("links = %+v, want 2 outside the code block", links)
+	}
+	if links[0].Text != "the demo" || links[0].Target != "live-demo" || links[0].Span.StartLine != 3 {
+		t.Errorf("link 0 = %+v, want \"the demo\" to #live-demo on line 3", links[0])
+	}
+
+	if codes := diagCodes(p); len(codes) != 1 || codes[0] != "broken-link" {
+		t.Fatalf("diagnostics = %v, want one broken-link", codes)
+	}
+	if d := p.Diagnostics[0]; d.Span.StartLine != 14 || !strings.Contains(d.Message, "#missing") {
+		t.Errorf("diagnostic = %+v, want #missing on line 14", d)
+	}
+}
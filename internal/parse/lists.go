@@ -0,0 +1,75 @@
+package parse
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// listItemRegex matches a bullet or ordered list item marker, capturing its
+// indentation, marker and the spaces after it.
+var listItemRegex = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+
+// listItemSteps returns the offsets in raw of every top-level list item
+// after the first on the slide, where incremental_lists starts a new reveal
+// step. Nested items and continuation lines stay with their parent, and
+// items in code or HTML blocks are ignored.
+func listItemSteps(raw string) []int {
+	var steps []int
+	var scanner blockScanner
+	contentCol := -1 // content column of the open top-level item; -1 outside a list
+	seen := false    // a top-level item has been found on the slide
+	blank := false   // previous line was blank
+
+	offset := 0
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		start := offset
+		offset += len(line)
+		text := strings.TrimRight(line, "\r\n")
+
+		inBlock := scanner.inBlock()
+		scanner.literal(text)
+		if strings.TrimSpace(text) == "" {
+			blank = true
+			continue
+		}
+		wasBlank := blank
+		blank = false
+
+		indent := indentWidth(text)
+		if inBlock || (contentCol >= 0 && indent >= contentCol) {
+			continue // inside a literal block or nested in the current item
+		}
+		if thematicBreakRegex.MatchString(text) || atxHeadingRegex.MatchString(text) {
+			contentCol = -1
+			continue
+		}
+		m := listItemRegex.FindStringSubmatch(text)
+		if m == nil {
+			if wasBlank {
+				contentCol = -1 // a paragraph after a blank line ends the list
+			}
+			continue
+		}
+
+		if seen {
+			steps = append(steps, start)
+		}
+		seen = true
+		contentCol = len(m[1]) + len(m[2]) + 1
+		if spaces := len(m[3]); spaces > 1 && spaces <= 4 {
+			contentCol += spaces - 1
+		}
+	}
+	return steps
+}
+
+// addSteps merges zero-width step boundaries at the given offsets into the
+// sorted pause marker ranges.
+func addSteps(pauses [][]int, offsets []int) [][]int {
+	for _, off := range offsets {
+		pauses = append(pauses, []int{off, off})
+	}
+	sort.SliceStable(pauses, func(i, j int) bool { return pauses[i][0] < pauses[j][0] })
+	return pauses
+}
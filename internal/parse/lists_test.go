@@ -0,0 +1,130 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListItemSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // first line of each step after the first
+	}{
+		{
+			name:  "bullets",
+			input: "# Title\n\n- one\n- two\n- three\n",
+			want:  []string{"- two", "- three"},
+		},
+		{
+			name:  "nested items stay with their parent",
+			input: "- one\n  - one.a\n  - one.b\n- two\n",
+			want:  []string{"- two"},
+		},
+		{
+			name:  "ordered with continuation",
+			input: "1. one\n   more about one\n\n   still one\n2. two\n10. ten\n",
+			want:  []string{"2. two", "10. ten"},
+		},
+		{
+			name:  "second list",
+			input: "- a\n- b\n\nBetween\n\n* c\n",
+			want:  []string{"- b", "* c"},
+		},
+		{
+			name:  "code blocks are ignored",
+			input: "- a\n\n```\n- not an item\n```\n\n    - indented code\n- b\n",
+			want:  []string{"- b"},
+		},
+		{
+			name:  "thematic break is not an item",
+			input: "- a\n\n* * *\n\n- b\n",
+			want:  []string{"- b"},
+		},
+		{
+			name:  "no list",
+			input: "# Title\n\nJust text.\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, off := range listItemSteps(tt.input) {
+				got = append(got, strings.SplitN(tt.input[off:], "\n", 2)[0])
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("steps = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIncrementalLists(t *testing.T) {
+	input := `---
+incremental_lists: true
+---
+# List
+
+- one
+  - nested
+- two
+- three
+---
+<!-- slide: {incremental_lists: false} -->
+- all
+- at once
+---
+# Columns
+<!-- column_layout: [1, 1] -->
+<!-- column: 0 -->
+- left
+<!-- column: 1 -->
+- right
+<!-- reset_layout -->`
+
+	p := ParsePresentation(input)
+	if len(p.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+	if !p.Frontmatter.IncrementalLists {
+		t.Error("frontmatter incremental_lists not decoded")
+	}
+
+	list := p.Slides[0]
+	if len(list.Chunks) != 3 {
+		t.Fatalf("list chunks = %d, want 3", len(list.Chunks))
+	}
+	if got := list.Chunks[0].Content; !strings.Contains(got, "- one\n  - nested\n") {
+		t.Errorf("first step = %q, want the first item with its nested item", got)
+	}
+	if got := strings.TrimSpace(list.Chunks[1].Content); got != "- two" {
+		t.Errorf("second step = %q, want %q", got, "- two")
+	}
+	if got := list.VisibleContent(2); !strings.Contains(got, "- one\n  - nested\n- two\n- three") {
+		t.Errorf("full content = %q, want one contiguous list", got)
+	}
+
+	if got := len(p.Slides[1].Chunks); got != 1 {
+		t.Errorf("slide with incremental_lists: false has %d chunks, want 1", got)
+	}
+
+	cols := p.Slides[2]
+	if len(cols.Chunks) != 2 {
+		t.Fatalf("column slide chunks = %d, want 2", len(cols.Chunks))
+	}
+	if got := cols.VisibleColumns(0); strings.TrimSpace(got[2]) != "" {
+		t.Errorf("right column shown at the first step: %q", got)
+	}
+}
+
+func TestParseIncrementalListsSlideOption(t *testing.T) {
+	p := ParsePresentation("<!-- slide: {incremental_lists: true} -->\n- a\n- b\n---\n- c\n- d\n")
+	if got := len(p.Slides[0].Chunks); got != 2 {
+		t.Errorf("slide with incremental_lists: true has %d chunks, want 2", got)
+	}
+	if got := len(p.Slides[1].Chunks); got != 1 {
+		t.Errorf("other slide has %d chunks, want 1", got)
+	}
+}
@@ -0,0 +1,138 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSlideMeta(t *testing.T) {
+	input := `<!-- slide: {id: intro, title: "Why", footer: false, class: centered} -->
+# Welcome
+---
+<!-- slide: id: details -->
+# Details`
+
+	pres := ParsePresentation(input)
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+
+	meta := pres.Slides[0].Meta
+	if meta.ID != "intro" || meta.Title != "Why" || meta.Class != "centered" || meta.ShowFooter() {
+		t.Errorf("slide 0 meta = %+v", meta)
+	}
+	if strings.Contains(pres.Slides[0].Chunks[0].Content, "slide:") {
+		t.Errorf("slide comment left in content: %q", pres.Slides[0].Chunks[0].Content)
+	}
+	if got := pres.Slides[1].Meta.ID; got != "details" {
+		t.Errorf("slide 1 id = %q, want details (braces are optional)", got)
+	}
+	if got := pres.Slides[1].Title(); got != "Details" {
+		t.Errorf("slide 1 title = %q, want Details", got)
+	}
+}
+
+func TestParseSlideMetaDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantMsg  string
+	}{
+		{
+			name:     "unknown field",
+			input:    "<!-- slide: {id: a, colour: red} -->\n# A",
+			wantCode: "invalid-slide-meta",
+			wantMsg:  "unknown field colour",
+		},
+		{
+			name:     "unknown class",
+			input:    "<!-- slide: {class: wide} -->\n# A",
+			wantCode: "invalid-slide-meta",
+			wantMsg:  `unknown class "wide"`,
+		},
+		{
+			name:     "wrong type",
+			input:    "<!-- slide: {footer: maybe} -->\n# A",
+			wantCode: "invalid-slide-meta",
+			wantMsg:  "cannot unmarshal",
+		},
+		{
+			name:     "two slide comments",
+			input:    "<!-- slide: {id: a} -->\n<!-- slide: {id: b} -->\n# A",
+			wantCode: "duplicate-slide-meta",
+			wantMsg:  "more than one",
+		},
+		{
+			name:     "duplicate id",
+			input:    "<!-- slide: {id: a} -->\n# A\n---\n<!-- slide: {id: a} -->\n# B",
+			wantCode: "duplicate-slide-id",
+			wantMsg:  `"a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := ParsePresentation(tt.input)
+			if len(pres.Diagnostics) != 1 {
+				t.Fatalf("diagnostics = %v, want one %s", diagCodes(pres), tt.wantCode)
+			}
+			d := pres.Diagnostics[0]
+			if d.Code != tt.wantCode || !strings.Contains(d.Message, tt.wantMsg) {
+				t.Errorf("diagnostic = %s %q, want %s containing %q", d.Code, d.Message, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseHiddenSlides(t *testing.T) {
+	p := ParsePresentation("# Shown\n---\n<!-- skip -->\n# Skipped\n---\n<!-- slide: {hidden: true} -->\n# Backup\n---\n<!-- skip -->\n<!-- slide: {id: extra} -->\n# Both")
+	if len(p.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", p.Diagnostics)
+	}
+	want := []bool{false, true, true, true}
+	for i, w := range want {
+		if got := p.Slides[i].Meta.Hidden; got != w {
+			t.Errorf("slide %d hidden = %v, want %v", i, got, w)
+		}
+	}
+	if p.Slides[3].Meta.ID != "extra" {
+		t.Errorf("skip should not drop the slide comment, meta = %+v", p.Slides[3].Meta)
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	input := `---
+vars:
+  part: Rollout
+---
+# Intro
+---
+<!-- section: Architecture -->
+# Overview
+---
+# Details
+---
+<!-- slide: {section: "{{ .part }}"} -->
+# Plan
+---
+<!-- section: Q&A -->
+<!-- section: Questions -->
+# Questions?`
+
+	pres := ParsePresentation(input)
+	want := []string{"", "Architecture", "", "Rollout", "Q&A"}
+	for i, slide := range pres.Slides {
+		if slide.Meta.Section != want[i] {
+			t.Errorf("slide %d section = %q, want %q", i, slide.Meta.Section, want[i])
+		}
+	}
+	if strings.Contains(pres.Slides[1].Chunks[0].Content, "section:") {
+		t.Errorf("section comment left in content: %q", pres.Slides[1].Chunks[0].Content)
+	}
+
+	if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != "duplicate-section" ||
+		pres.Diagnostics[0].Span.StartLine != 16 {
+		t.Errorf("diagnostics = %v, want one duplicate-section on line 16", pres.Diagnostics)
+	}
+}
@@ -0,0 +1,109 @@
+package parse
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+var (
+	notesDivOpenRegex  = regexp.MustCompile(`^ {0,3}:{3,}[ \t]*(?:notes|\{[ \t]*\.notes[ \t]*\})[ \t]*$`)
+	notesDivCloseRegex = regexp.MustCompile(`^ {0,3}:{3,}[ \t]*$`)
+)
+
+// note is a speaker note and the range of the slide it was taken from.
+type note struct {
+	text       string
+	start, end int
+}
+
+// extractNoteDivs removes pandoc-style ::: notes blocks from raw, outside
+// literal blocks, returning their markdown. The blocks are blanked rather
+// than cut so offsets into raw stay valid. A block left open runs to the
+// end of the slide.
+func extractNoteDivs(raw string) (string, []note) {
+	var notes []note
+	var outer, inner blockScanner
+	blanked := []byte(raw)
+
+	open := -1 // offset of the open block's first line, or -1
+	var body strings.Builder
+	closeNote := func(end int) {
+		notes = append(notes, note{text: strings.TrimSpace(body.String()), start: open, end: end})
+		for i := open; i < end; i++ {
+			if blanked[i] != '\n' {
+				blanked[i] = ' '
+			}
+		}
+		open = -1
+		body.Reset()
+	}
+
+	offset := 0
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		start := offset
+		offset += len(line)
+		text := strings.TrimRight(line, "\r\n")
+
+		if open < 0 {
+			if !outer.literal(text) && notesDivOpenRegex.MatchString(text) {
+				open = start
+				inner = blockScanner{}
+			}
+			continue
+		}
+		if !inner.inBlock() && notesDivCloseRegex.MatchString(text) {
+			closeNote(start + len(text))
+			continue
+		}
+		inner.literal(text)
+		body.WriteString(line)
+	}
+	if open >= 0 {
+		closeNote(len(raw))
+	}
+	return string(blanked), notes
+}
+
+// blockValue returns the value of a command that may continue over several
+// lines: text on the comment's first line, then the following lines with
+// their common indentation removed so lists, code and YAML keep their
+// structure.
+func blockValue(s string) string {
+	first, rest, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(strings.TrimSpace(first) + "\n" + dedent(rest))
+}
+
+// dedent removes the indentation shared by every non-blank line of s.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || lead < indent {
+			indent = lead
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		} else {
+			lines[i] = strings.TrimLeft(line, " \t")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addNotes stores notes on the slide in source order.
+func (p *parser) addNotes(slide *model.Slide, notes []note, base int) {
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].start < notes[j].start })
+	for _, n := range notes {
+		slide.SpeakerNotes = append(slide.SpeakerNotes, n.text)
+		slide.NoteSpans = append(slide.NoteSpans, p.src.span(base+n.start, base+n.end))
+	}
+}
@@ -0,0 +1,78 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNoteDivs(t *testing.T) {
+	input := `# Title
+
+<!-- speaker_note: First -->
+
+::: notes
+- **Bold** point
+- ` + "`code`" + ` point
+:::
+
+Body
+
+::: {.notes}
+` + "```" + `
+:::
+` + "```" + `
+:::
+
+` + "```markdown" + `
+::: notes
+not a note
+:::
+` + "```"
+
+	pres := ParsePresentation(input)
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	s := pres.Slides[0]
+
+	want := []string{"First", "- **Bold** point\n- `code` point", "```\n:::\n```"}
+	if strings.Join(s.SpeakerNotes, "|") != strings.Join(want, "|") {
+		t.Fatalf("notes = %q, want %q", s.SpeakerNotes, want)
+	}
+	if len(s.NoteSpans) != 3 || s.NoteSpans[1].StartLine != 5 || s.NoteSpans[1].EndLine != 8 {
+		t.Errorf("note spans = %+v, want the div on lines 5-8", s.NoteSpans)
+	}
+
+	content := s.VisibleContent(0)
+	if strings.Contains(content, "Bold") || strings.Contains(content, "::: notes\n-") {
+		t.Errorf("note div left in content: %q", content)
+	}
+	if !strings.Contains(content, "Body") || !strings.Contains(content, "not a note") {
+		t.Errorf("content = %q, want the body and the fenced example kept", content)
+	}
+	if len(s.CodeBlocks) != 1 {
+		t.Errorf("code blocks = %d, want only the one outside the notes", len(s.CodeBlocks))
+	}
+}
+
+func TestParseNoteDivUnclosed(t *testing.T) {
+	pres := ParsePresentation("# Title\n\n::: notes\nRuns to the end\n---\n# Next")
+	if got := pres.Slides[0].SpeakerNotes; len(got) != 1 || got[0] != "Runs to the end" {
+		t.Errorf("notes = %q, want the rest of the slide", got)
+	}
+	if got := pres.Slides[1].SpeakerNotes; len(got) != 0 {
+		t.Errorf("next slide notes = %q, want none", got)
+	}
+}
+
+func TestParseSlideMetaBlock(t *testing.T) {
+	input := "<!-- slide:\n  id: intro\n  title: Why\n-->\n# Welcome"
+
+	pres := ParsePresentation(input)
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	if meta := pres.Slides[0].Meta; meta.ID != "intro" || meta.Title != "Why" {
+		t.Errorf("meta = %+v, want id intro and title Why", meta)
+	}
+}
@@ -0,0 +1,55 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// isOnlyCommand reports whether the inner text of a comment opens or
+// closes an only region.
+func isOnlyCommand(inner string) bool {
+	cmd, ok := parseCommand(inner)
+	return ok && (cmd.Type == model.CmdOnly || cmd.Type == model.CmdEndOnly)
+}
+
+// decodeOnly decodes the profiles of an only comment: one name, or several
+// as a YAML list whose brackets may be omitted.
+func decodeOnly(value string) (model.Profiles, error) {
+	if !strings.HasPrefix(value, "[") {
+		value = "[" + value + "]"
+	}
+	var profiles model.Profiles
+	if err := yaml.Unmarshal([]byte(value), &profiles); err != nil {
+		return nil, errors.New("want a profile name or a list of names")
+	}
+	for _, name := range profiles {
+		if name == "" {
+			return nil, errors.New("profile names cannot be empty")
+		}
+	}
+	return profiles, nil
+}
+
+// filterOnly removes the only and end_only comments from raw, outside
+// code, along with the regions between them that are not meant for the
+// selected profile. The text is cut from the parsed text too, as cutter
+// describes.
+func (p *parser) filterOnly(raw string, base int) string {
+	regions := p.regions(raw, base, model.CmdOnly, model.CmdEndOnly, "only")
+	c := cutter{raw: raw}
+	for _, r := range regions {
+		profiles, err := decodeOnly(r.open.Command.Value)
+		if err != nil {
+			p.report(model.SeverityWarning, "invalid-command", base+r.open.Start, base+r.open.End,
+				"only comment: %s", err)
+		}
+		if !profiles.Allows(p.profile) {
+			c.cut(r.inner[0], r.inner[1])
+		}
+	}
+	c.cutComments(isOnlyCommand)
+	return c.apply(p.src, base)
+}
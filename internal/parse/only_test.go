@@ -0,0 +1,205 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+const onlyDeck = `---
+profile: external
+---
+# Roadmap
+
+Shipping in Q3.
+
+<!-- only: internal -->
+Pending legal review.
+<!-- end_only -->
+
+<!-- only: [external, partner] -->
+Ask us about early access.
+<!-- end_only -->
+
+` + "```markdown\n<!-- only: internal -->\nkept as written\n<!-- end_only -->\n```" + `
+---
+<!-- slide: {only: internal} -->
+# Revenue
+---
+<!-- slide: {only: [external, partner]} -->
+# Customers
+---
+# Questions?`
+
+func TestParseOnly(t *testing.T) {
+	tests := []struct {
+		profile    string
+		wantTitles string
+		want       []string // text in the first slide
+		dropped    []string // text not in it
+	}{
+		{
+			profile:    "", // the frontmatter's profile
+			wantTitles: "Roadmap|Customers|Questions?",
+			want:       []string{"Shipping", "early access", "kept as written"},
+			dropped:    []string{"legal review"},
+		},
+		{
+			profile:    "internal",
+			wantTitles: "Roadmap|Revenue|Questions?",
+			want:       []string{"Shipping", "legal review"},
+			dropped:    []string{"early access"},
+		},
+		{
+			profile:    "partner",
+			wantTitles: "Roadmap|Customers|Questions?",
+			want:       []string{"early access"},
+			dropped:    []string{"legal review"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("profile "+tt.profile, func(t *testing.T) {
+			pres := Parse(onlyDeck, Options{Profile: tt.profile})
+			if len(pres.Diagnostics) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+			}
+
+			var titles []string
+			for _, s := range pres.Slides {
+				titles = append(titles, s.Title())
+			}
+			if got := strings.Join(titles, "|"); got != tt.wantTitles {
+				t.Errorf("slides = %s, want %s", got, tt.wantTitles)
+			}
+
+			content := pres.Slides[0].VisibleContent(0)
+			for _, text := range tt.want {
+				if !strings.Contains(content, text) {
+					t.Errorf("content = %q, want it to contain %q", content, text)
+				}
+			}
+			for _, text := range tt.dropped {
+				if strings.Contains(content, text) {
+					t.Errorf("content = %q, want %q dropped", content, text)
+				}
+			}
+			if n := strings.Count(content, "only"); n != 2 {
+				t.Errorf("content = %q, want only comments removed outside the code block", content)
+			}
+		})
+	}
+}
+
+func TestParseOnlyWithoutProfile(t *testing.T) {
+	pres := ParsePresentation(strings.TrimPrefix(onlyDeck, "---\nprofile: external\n---\n"))
+	if len(pres.Slides) != 4 {
+		t.Fatalf("slides = %d, want all 4 when no profile is selected", len(pres.Slides))
+	}
+	content := pres.Slides[0].VisibleContent(0)
+	if !strings.Contains(content, "legal review") || !strings.Contains(content, "early access") {
+		t.Errorf("content = %q, want every region kept", content)
+	}
+}
+
+func TestParseOnlyLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "region at the start of a line",
+			input: "<!-- only: external -->Ask us<!-- end_only --> about access.",
+			want:  "Ask us about access.",
+		},
+		{
+			name:  "text after a closing comment",
+			input: "<!-- only: internal -->\nSecret\n<!-- end_only -->after",
+			want:  "after",
+		},
+		{
+			name:  "dropped region in a tight list",
+			input: "- one\n<!-- only: internal -->\n- secret\n<!-- end_only -->\n- two",
+			want:  "- one\n- two",
+		},
+		{
+			name:  "kept region in a tight list",
+			input: "- one\n  <!-- only: external -->\n- two\n  <!-- end_only -->\n- three",
+			want:  "- one\n- two\n- three",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := Parse(tt.input, Options{Profile: "external"})
+			if got := pres.Slides[0].VisibleContent(0); got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOnlyKeepsLines(t *testing.T) {
+	input := "<!-- only: internal -->\nSecret\n<!-- end_only -->\n{{ .a }}\n---\n# Two\n\n{{ .b }}"
+	pres := Parse(input, Options{Profile: "external"})
+	var lines []int
+	for _, d := range pres.Diagnostics {
+		lines = append(lines, d.Span.StartLine)
+	}
+	if !intSliceEqual(lines, []int{4, 8}) {
+		t.Errorf("diagnostics on lines %v, want [4 8] after the region is cut", lines)
+	}
+	if got := pres.Slides[1].Chunks[0].Span.StartLine; got != 6 {
+		t.Errorf("second slide starts on line %d, want 6", got)
+	}
+}
+
+func TestParseOnlyDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantLine int
+		dropped  string
+	}{
+		{
+			name:     "end without only",
+			input:    "# One\n\nText\n<!-- end_only -->",
+			wantCode: "unmatched-only",
+			wantLine: 4,
+		},
+		{
+			name:     "unclosed region runs to the end of the slide",
+			input:    "# One\n\n<!-- only: internal -->\nSecret\n---\n# Two",
+			wantCode: "unmatched-only",
+			wantLine: 3,
+			dropped:  "Secret",
+		},
+		{
+			name:     "nested region",
+			input:    "# One\n\n<!-- only: internal -->\n<!-- only: partner -->\nSecret\n<!-- end_only -->",
+			wantCode: "unmatched-only",
+			wantLine: 4,
+			dropped:  "Secret",
+		},
+		{
+			name:     "invalid profiles",
+			input:    "# One\n\n<!-- only: {a: b} -->\nShown\n<!-- end_only -->",
+			wantCode: "invalid-command",
+			wantLine: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := Parse(tt.input, Options{Profile: "external"})
+			if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != tt.wantCode ||
+				pres.Diagnostics[0].Span.StartLine != tt.wantLine {
+				t.Fatalf("diagnostics = %v, want one %s on line %d", pres.Diagnostics, tt.wantCode, tt.wantLine)
+			}
+			if tt.dropped != "" && strings.Contains(pres.Slides[0].VisibleContent(0), tt.dropped) {
+				t.Errorf("content = %q, want %q dropped", pres.Slides[0].VisibleContent(0), tt.dropped)
+			}
+		})
+	}
+}
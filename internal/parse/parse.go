@@ -1,135 +1,567 @@
 package parse
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jedwards1230/deck/internal/model"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	pauseRegex  = regexp.MustCompile(`(?m)^\s*<!--\s*pause\s*-->\s*$`)
-	columnRegex = regexp.MustCompile(`(?m)^\s*<!--\s*column:\s*\d+\s*-->\s*$`)
+	pauseRegex = regexp.MustCompile(`(?m)^\s*<!--\s*pause\s*-->\s*$`)
+
+	yamlLineRegex         = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlUnknownFieldRegex = regexp.MustCompile(`^field (\S+) not found in type .*$`)
 )
 
+// Options configures Parse.
+type Options struct {
+	// Path is the file the content was read from. Include directives
+	// resolve relative to its directory; when empty, they resolve relative
+	// to the working directory. For a directory deck read with ReadDeck,
+	// Path is the directory.
+	Path string
+
+	// Vars set template variables, overriding the frontmatter's vars.
+	Vars map[string]string
+
+	// Profile selects the audience profile, overriding the frontmatter's
+	// profile. Slides and only regions meant for other profiles are
+	// dropped.
+	Profile string
+
+	// Lang selects the language of lang regions, overriding the
+	// frontmatter's lang. Regions in other languages are dropped.
+	Lang string
+
+	// Strict reports every frontmatter key deck does not know as an
+	// error, instead of keeping it in Frontmatter.Extra.
+	Strict bool
+
+	// NotesLang, when set, keeps the speaker notes of lang regions in this
+	// language instead of Lang, for a presenter who reads notes in their
+	// own language.
+	NotesLang string
+}
+
+// parser holds the state shared across one Parse call.
+type parser struct {
+	root       string // opts.Path
+	rootIsDir  bool
+	src        *sourceMap
+	diags      []model.Diagnostic
+	sources    []string              // files read, in order
+	missing    []string              // files named but not readable
+	including  []string              // absolute paths of the files being expanded
+	includedFM []includedFrontmatter // frontmatter of included files, in order
+	slideIDs   map[string]bool
+	anchors    map[string]int    // uses of each heading slug so far
+	rootFM     *frontmatterBlock // the deck's own frontmatter, if any
+
+	incremental bool              // frontmatter incremental_lists
+	vars        map[string]string // frontmatter vars with Options.Vars applied
+	align       string            // frontmatter align
+	valign      string            // frontmatter valign
+	profile     string            // frontmatter profile with Options.Profile applied
+	lang        string            // frontmatter lang with Options.Lang applied
+	notesLang   string            // Options.NotesLang
+	languages   []string          // languages of lang regions, in order of appearance
+	strict      bool              // Options.Strict
+}
+
+// incrementalLists reports whether a slide reveals its list items one at a
+// time, letting the slide's own option override the frontmatter.
+func (p *parser) incrementalLists(meta model.SlideMeta) bool {
+	if meta.IncrementalLists != nil {
+		return *meta.IncrementalLists
+	}
+	return p.incremental
+}
+
+// report records a diagnostic for the range [start, end) of the parsed text.
+func (p *parser) report(sev model.Severity, code string, start, end int, format string, args ...any) {
+	p.reportSpan(sev, code, p.src.span(start, end), format, args...)
+}
+
+// reportSpan records a diagnostic at span.
+func (p *parser) reportSpan(sev model.Severity, code string, span model.Span, format string, args ...any) {
+	p.diags = append(p.diags, model.Diagnostic{
+		Severity: sev,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Span:     span,
+	})
+}
+
 // ParsePresentation parses raw markdown content into a Presentation.
 func ParsePresentation(content string) *model.Presentation {
+	return Parse(content, Options{})
+}
+
+// Parse parses raw markdown content into a Presentation, expanding
+// include directives relative to opts.Path.
+func Parse(content string, opts Options) *model.Presentation {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	p := &parser{root: opts.Path, src: newSourceMap(), slideIDs: make(map[string]bool), anchors: make(map[string]int), strict: opts.Strict}
+	p.src.addFile(opts.Path, content)
+	p.addSource(opts.Path)
+	if opts.Path != "" {
+		p.including = []string{absPath(opts.Path)}
+		if info, err := os.Stat(opts.Path); err == nil && info.IsDir() {
+			p.rootIsDir = true
+		}
+	}
+
 	// Extract frontmatter BEFORE splitting slides, since frontmatter uses
 	// the same --- delimiter as slide boundaries.
-	fm, remaining := ParseFrontmatter(content)
+	block, hasFM := p.locateFrontmatter(opts.Path, content)
+	bodyStart := 0
+	if hasFM {
+		bodyStart = block.bodyStart
+	}
+	p.src.write(opts.Path, 0, content[:bodyStart])
+	p.expand(opts.Path, content, bodyStart, 0)
+	if hasFM {
+		p.appendSlideFiles(opts.Path, block, bodyStart)
+	}
+	text := p.src.String()
 
-	rawSlides := SplitSlides(remaining)
-	if len(rawSlides) == 0 {
-		return &model.Presentation{Frontmatter: fm}
+	var fm model.Frontmatter
+	if hasFM || len(p.includedFM) > 0 {
+		fm = p.decodeFrontmatter(opts.Path, block, hasFM)
 	}
 
+	if hasFM {
+		p.rootFM = &block
+	}
+	p.incremental = fm.IncrementalLists
+	p.align, p.valign = p.placement(fm)
+	p.vars = deckVars(fm.Vars, opts.Vars)
+	if opts.Profile != "" {
+		fm.Profile = opts.Profile
+	}
+	p.profile = fm.Profile
+	p.addLanguage(fm.Lang)
+	if opts.Lang != "" {
+		fm.Lang = opts.Lang
+	}
+	if fm.Lang == "" {
+		fm.Lang = firstLanguage(text[bodyStart:])
+	}
+	p.lang, p.notesLang = fm.Lang, opts.NotesLang
+	fm.Author = expandVars(fm.Author, p.vars)
+	fm.Date = expandVars(fm.Date, p.vars)
+	fm.Footer = expandVars(fm.Footer, p.vars)
+	rawSlides := splitSlides(text[bodyStart:], p.splitRule(fm))
 	slides := make([]model.Slide, 0, len(rawSlides))
+	cut := 0 // bytes earlier slides cut from the parsed text
 	for _, raw := range rawSlides {
-		slide := parseSlide(raw)
+		size := p.src.size()
+		slide := p.parseSlide(raw.text, bodyStart+raw.start-cut)
+		cut += size - p.src.size()
+		if !slide.Meta.Only.Allows(p.profile) {
+			continue
+		}
 		slides = append(slides, slide)
 	}
+	if len(slides) == 0 {
+		slides = nil
+	}
+	p.expandTOCs(slides)
+	p.checkLinks(slides)
+
+	sort.SliceStable(p.diags, func(i, j int) bool {
+		a, b := p.diags[i].Span, p.diags[j].Span
+		if a.File != b.File {
+			return a.File == opts.Path
+		}
+		return a.Start < b.Start
+	})
 
 	return &model.Presentation{
 		Slides:      slides,
 		Frontmatter: fm,
+		Diagnostics: p.diags,
+		Sources:     p.sources,
+		Missing:     p.missing,
+		Languages:   p.languages,
 	}
 }
 
-func parseSlide(raw string) model.Slide {
-	var slide model.Slide
+// locateFrontmatter finds the deck's frontmatter block, reporting one that
+// is opened but never closed.
+func (p *parser) locateFrontmatter(file, content string) (frontmatterBlock, bool) {
+	block, ok := splitFrontmatter(content)
+	if !ok {
+		if start, _, opened := openingDelimiter(content); opened {
+			p.reportSpan(model.SeverityError, "unclosed-frontmatter", p.src.fileSpan(file, start, start+3),
+				"frontmatter block is never closed; add a --- line after it")
+		}
+	}
+	return block, ok
+}
 
-	// Extract speaker notes and layout commands first
-	cmds, _ := ExtractCommands(raw)
-	var layout *model.ColumnLayout
-	for _, cmd := range cmds {
-		switch cmd.Command.Type {
-		case model.CmdSpeakerNote:
-			slide.SpeakerNotes = append(slide.SpeakerNotes, cmd.Command.Value)
-		case model.CmdColumnLayout:
-			layout = &model.ColumnLayout{Ratios: cmd.Command.Ratios}
+// appendSlideFiles splices the files named by the frontmatter's slides
+// list after the deck's own slides, each starting a new slide.
+func (p *parser) appendSlideFiles(file string, block frontmatterBlock, bodyStart int) {
+	var doc struct {
+		Slides []yaml.Node `yaml:"slides"`
+	}
+	// Decode errors are reported when the frontmatter itself is decoded.
+	_ = yaml.Unmarshal([]byte(block.yaml), &doc)
+
+	firstLine := p.src.line(file, block.yamlStart)
+	for _, entry := range doc.Slides {
+		if entry.Kind != yaml.ScalarNode || entry.Value == "" {
+			continue
+		}
+		span := p.src.lineSpan(file, firstLine+entry.Line-1)
+		resolved, content, ok := p.load(file, entry.Value, span, 0)
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(p.src.String()[bodyStart:]) != "" {
+			p.src.write(file, span.Start, "\n---\n")
 		}
+		p.splice(resolved, content, 0)
 	}
-	slide.Layout = layout
+}
 
-	// Extract column content if layout is present
-	if layout != nil {
-		slide.Columns = extractColumns(raw, len(layout.Ratios))
+// splitRule builds the slide splitting rule from the frontmatter, reporting
+// settings it cannot use and falling back to the default for them.
+func (p *parser) splitRule(fm model.Frontmatter) splitRule {
+	opts := splitOptions{Separator: fm.Separator, SlideLevel: fm.SlideLevel}
+	if opts.SlideLevel < 0 || opts.SlideLevel > 6 {
+		p.reportSpan(model.SeverityError, "frontmatter-value", p.frontmatterKeySpan("slide_level"),
+			"slide_level must be between 1 and 6, got %d", opts.SlideLevel)
+		opts.SlideLevel = 0
+	}
+	rule, err := newSplitRule(opts)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "frontmatter-value", p.frontmatterKeySpan("separator"),
+			"separator is not a valid regular expression: %s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+		rule, _ = newSplitRule(splitOptions{SlideLevel: opts.SlideLevel})
 	}
+	return rule
+}
 
-	// Split at pause markers to create chunks
-	parts := pauseRegex.Split(raw, -1)
-	for _, part := range parts {
-		_, cleaned := extractNonPauseCommands(part)
-		slide.Chunks = append(slide.Chunks, model.Chunk{Content: cleaned})
+// placement returns the frontmatter's default slide alignment, reporting
+// values it does not know and ignoring them.
+func (p *parser) placement(fm model.Frontmatter) (align, valign string) {
+	align, valign = fm.Align, fm.VAlign
+	if align != "" && !model.ValidAlign(align) {
+		p.reportSpan(model.SeverityError, "frontmatter-value", p.frontmatterKeySpan("align"),
+			"align must be left, center or right, got %q", align)
+		align = ""
+	}
+	if valign != "" && !model.ValidVAlign(valign) {
+		p.reportSpan(model.SeverityError, "frontmatter-value", p.frontmatterKeySpan("valign"),
+			"valign must be top, middle or bottom, got %q", valign)
+		valign = ""
 	}
+	return align, valign
+}
 
-	if len(slide.Chunks) == 0 {
-		slide.Chunks = []model.Chunk{{Content: raw}}
+// frontmatterKeySpan returns the line that sets key in the deck's
+// frontmatter or, failing that, in the first included file that sets it.
+func (p *parser) frontmatterKeySpan(key string) model.Span {
+	var blocks []includedFrontmatter
+	if p.rootFM != nil {
+		blocks = append(blocks, includedFrontmatter{file: p.root, frontmatterBlock: *p.rootFM})
 	}
+	blocks = append(blocks, p.includedFM...)
 
-	return slide
+	for _, b := range blocks {
+		var doc yaml.Node
+		if yaml.Unmarshal([]byte(b.yaml), &doc) != nil || len(doc.Content) == 0 {
+			continue
+		}
+		fields := doc.Content[0].Content
+		for i := 0; i+1 < len(fields); i += 2 {
+			if fields[i].Value == key {
+				return p.src.lineSpan(b.file, p.src.line(b.file, b.yamlStart)+fields[i].Line-1)
+			}
+		}
+	}
+	return p.src.fileSpan(p.root, 0, 0)
+}
+
+// decodeFrontmatter decodes the deck's frontmatter over that of its
+// included files, so the including deck wins and, among included files,
+// the first one to set a field wins.
+func (p *parser) decodeFrontmatter(file string, block frontmatterBlock, hasFM bool) model.Frontmatter {
+	var fm model.Frontmatter
+	for i := len(p.includedFM) - 1; i >= 0; i-- {
+		inc := p.includedFM[i]
+		if err := yaml.Unmarshal([]byte(inc.yaml), &fm); err != nil {
+			p.reportYAMLError(err, inc.file, inc.yamlStart)
+		}
+		p.checkFrontmatterKeys(inc.file, inc.frontmatterBlock)
+	}
+	if hasFM {
+		if err := yaml.Unmarshal([]byte(block.yaml), &fm); err != nil {
+			p.reportYAMLError(err, file, block.yamlStart)
+		}
+		p.checkFrontmatterKeys(file, block)
+	}
+	applyFrontmatterDefaults(&fm)
+	return fm
 }
 
-// extractColumns splits slide content into per-column buckets using
-// <!-- column: N --> markers. Content before the first column marker
-// is discarded (it's typically just the layout command).
-func extractColumns(raw string, numColumns int) []string {
-	columns := make([]string, numColumns)
+// reportYAMLError turns a YAML decode error into diagnostics, mapping the
+// decoder's block-relative line numbers onto file.
+func (p *parser) reportYAMLError(err error, file string, yamlStart int) {
+	msgs := []string{strings.TrimPrefix(err.Error(), "yaml: ")}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		msgs = typeErr.Errors
+	}
 
-	// Split at column markers
-	parts := columnRegex.Split(raw, -1)
-	markers := columnRegex.FindAllString(raw, -1)
+	firstLine := p.src.line(file, yamlStart)
+	for _, msg := range msgs {
+		span := p.src.fileSpan(file, yamlStart, yamlStart)
+		if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			span = p.src.lineSpan(file, firstLine+n-1)
+			msg = m[2]
+		}
+		p.reportSpan(model.SeverityError, "frontmatter-yaml", span, "frontmatter: %s", msg)
+	}
+}
 
-	if len(markers) == 0 {
-		return nil
+// parseSlide parses one slide whose text starts at byte offset base of the
+// source.
+func (p *parser) parseSlide(raw string, base int) model.Slide {
+	slide := model.Slide{Span: p.src.span(base, base+len(raw))}
+	raw = p.filterOnly(raw, base)
+	raw = p.filterLang(raw, base)
+	p.checkVars(raw, base, codeBlockRanges(raw))
+
+	// ::: notes blocks and footnote definitions are blanked so the rest
+	// of the slide parses without them
+	raw, notes := extractNoteDivs(raw)
+	raw, footnotes := p.extractFootnotes(raw, base)
+	codeRanges := codeBlockRanges(raw)
+	for _, r := range codeRanges {
+		slide.CodeBlocks = append(slide.CodeBlocks, model.CodeBlock{
+			Language: fenceLanguage(raw[r[0]:r[1]]),
+			Span:     p.src.span(base+r[0], base+r[1]),
+		})
 	}
+	p.checkComments(raw, base, codeRanges)
+	slide.Anchors = p.extractAnchors(raw)
+	slide.Links = p.extractLinks(raw, base, codeRanges)
 
-	// Parse column indices from markers
-	currentCol := -1
-	for i, marker := range markers {
-		cmds, _ := ExtractCommands(marker)
-		for _, cmd := range cmds {
-			if cmd.Command.Type == model.CmdColumn {
-				currentCol = cmd.Command.Column
+	// Extract speaker notes and slide commands first
+	cmds, _ := ExtractCommands(raw)
+	metaSeen, skip := false, false
+	section := ""
+	for _, cmd := range cmds {
+		switch cmd.Command.Type {
+		case model.CmdSpeakerNote:
+			notes = append(notes, note{text: cmd.Command.Value, start: cmd.Start, end: cmd.End})
+		case model.CmdSkip:
+			skip = true
+		case model.CmdSection:
+			if section != "" {
+				p.report(model.SeverityWarning, "duplicate-section", base+cmd.Start, base+cmd.End,
+					"slide already starts section %q; only the first section comment is used", section)
+				continue
+			}
+			section = cmd.Command.Value
+		case model.CmdTOC:
+			if _, err := decodeTOC(cmd.Command.Value); err != nil {
+				p.report(model.SeverityWarning, "invalid-command", base+cmd.Start, base+cmd.End,
+					"toc comment: %s", err)
+			}
+		case model.CmdSlideMeta:
+			if metaSeen {
+				p.report(model.SeverityWarning, "duplicate-slide-meta", base+cmd.Start, base+cmd.End,
+					"slide has more than one slide comment; only the first is used")
+				continue
 			}
+			metaSeen = true
+			slide.Meta = p.decodeSlideMeta(cmd.Command.Value, base+cmd.Start, base+cmd.End)
 		}
-
-		// parts[i+1] is the content after this marker
-		if currentCol >= 0 && currentCol < numColumns && i+1 < len(parts) {
-			content := parts[i+1]
-			// Strip non-column commands (speaker notes, layout, reset_layout, pauses)
-			_, cleaned := extractNonPauseCommands(content)
-			// Also strip pause markers
-			cleaned = pauseRegex.ReplaceAllString(cleaned, "")
-			columns[currentCol] += cleaned
+	}
+	p.addNotes(&slide, notes, base)
+	if skip {
+		slide.Meta.Hidden = true
+	}
+	if section != "" {
+		slide.Meta.Section = section
+	}
+	slide.Align, slide.VAlign = p.align, p.valign
+	// Title and section slides are centered on any axis the frontmatter
+	// leaves to the default.
+	if isTitleSlide(raw) {
+		if slide.Align == "" {
+			slide.Align = model.AlignCenter
+		}
+		if slide.VAlign == "" {
+			slide.VAlign = model.VAlignMiddle
 		}
 	}
+	if slide.Meta.Class == "centered" {
+		slide.Align, slide.VAlign = model.AlignCenter, model.VAlignMiddle
+	}
+
+	// Split at pause markers to create chunks
+	pauses := pauseRegex.FindAllStringIndex(raw, -1)
+	if p.incrementalLists(slide.Meta) {
+		pauses = addSteps(pauses, listItemSteps(raw))
+	}
+	start := 0
+	bounds := append(pauses, []int{len(raw), len(raw)})
+	for _, loc := range bounds {
+		_, cleaned := extractNonPauseCommands(raw[start:loc[0]])
+		slide.Chunks = append(slide.Chunks, model.Chunk{
+			Content: cleaned,
+			Span:    p.src.span(base+start, base+loc[0]),
+		})
+		start = loc[1]
+	}
 
-	return columns
+	p.extractGrid(&slide, raw, base, codeRanges, pauses)
+	p.addFootnotes(&slide, raw, base, footnotes, codeRanges, pauses)
+	p.expandSlideVars(&slide)
+
+	return slide
 }
 
-func extractNonPauseCommands(content string) ([]CommandWithPosition, string) {
-	var commands []CommandWithPosition
+// isTitleSlide reports whether raw holds nothing but headings outside
+// comments, as a title or section slide does.
+func isTitleSlide(raw string) bool {
+	found := false
+	for _, line := range strings.Split(commentRegex.ReplaceAllString(raw, ""), "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+		case atxHeadingRegex.MatchString(line):
+			found = true
+		default:
+			return false
+		}
+	}
+	return found
+}
+
+// slideClasses lists the values a slide comment's class may take.
+var slideClasses = map[string]bool{"centered": true}
 
-	cleaned := commentRegex.ReplaceAllStringFunc(content, func(match string) string {
-		sub := commentRegex.FindStringSubmatch(match)
-		if len(sub) < 2 {
-			return match
+// decodeSlideMeta decodes the YAML of a slide comment spanning [start, end)
+// of the parsed text. The surrounding braces of a flow mapping may be
+// omitted, and a comment spanning several lines may hold a block mapping.
+// Invalid fields are reported and left unset.
+func (p *parser) decodeSlideMeta(value string, start, end int) model.SlideMeta {
+	switch {
+	case strings.HasPrefix(value, "{"):
+	case strings.Contains(value, "\n"):
+		value = blockValue(value)
+	default:
+		value = "{" + value + "}"
+	}
+	var meta model.SlideMeta
+	dec := yaml.NewDecoder(strings.NewReader(value))
+	dec.KnownFields(true)
+	if err := dec.Decode(&meta); err != nil {
+		msgs := []string{err.Error()}
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			msgs = typeErr.Errors
+		}
+		for _, msg := range msgs {
+			msg = strings.TrimPrefix(msg, "yaml: ")
+			if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+				msg = m[2]
+			}
+			msg = yamlUnknownFieldRegex.ReplaceAllString(msg, "unknown field $1")
+			p.report(model.SeverityWarning, "invalid-slide-meta", start, end, "slide comment: %s", msg)
 		}
+	}
 
-		inner := strings.TrimSpace(sub[1])
-		if inner == "pause" {
-			return match
+	if meta.Class != "" && !slideClasses[meta.Class] {
+		p.report(model.SeverityWarning, "invalid-slide-meta", start, end,
+			"slide comment: unknown class %q", meta.Class)
+		meta.Class = ""
+	}
+	if meta.ID != "" {
+		if p.slideIDs[meta.ID] {
+			p.report(model.SeverityWarning, "duplicate-slide-id", start, end,
+				"slide id %q is already used by an earlier slide", meta.ID)
 		}
+		p.slideIDs[meta.ID] = true
+	}
+	return meta
+}
 
-		cmd, ok := parseCommand(inner)
+// checkComments reports HTML comments outside code blocks that look like
+// commands but are not recognized, since they are otherwise silently kept
+// as content.
+func (p *parser) checkComments(raw string, base int, codeRanges [][2]int) {
+	for _, loc := range commentRegex.FindAllStringSubmatchIndex(raw, -1) {
+		if inRanges(loc[0], codeRanges) {
+			continue
+		}
+		inner := strings.TrimSpace(raw[loc[2]:loc[3]])
+		if cmd, ok := parseCommand(inner); ok {
+			switch cmd.Type {
+			case model.CmdInclude:
+				p.report(model.SeverityWarning, "inline-include", base+loc[0], base+loc[1],
+					"include directive must be on its own line; it was ignored")
+			case model.CmdFile:
+				p.report(model.SeverityWarning, "inline-file", base+loc[0], base+loc[1],
+					"file directive must be on its own line; it was ignored")
+			case model.CmdTable:
+				p.report(model.SeverityWarning, "inline-table", base+loc[0], base+loc[1],
+					"table directive must be on its own line; it was ignored")
+			}
+			continue
+		}
+		name, ok := commandName(inner)
 		if !ok {
-			return match
+			continue
+		}
+		if knownCommands[name] {
+			p.report(model.SeverityWarning, "invalid-command", base+loc[0], base+loc[1],
+				"invalid %s command %q", name, inner)
+		} else {
+			p.report(model.SeverityWarning, "unknown-command", base+loc[0], base+loc[1],
+				"unknown command %q", name)
 		}
+	}
+}
 
-		commands = append(commands, CommandWithPosition{Command: cmd})
-		return ""
+// extractNonPauseCommands strips every command except pause, which splits
+// chunks, and toc, which is expanded once the whole deck is parsed.
+func extractNonPauseCommands(content string) ([]CommandWithPosition, string) {
+	return extractCommands(content, func(inner string) bool {
+		return inner != "pause" && !isTOCCommand(inner)
 	})
+}
 
-	return commands, cleaned
+// trimRange narrows [start, end) of s to exclude surrounding whitespace,
+// for matches of patterns that absorb neighbouring blank lines.
+func trimRange(s string, start, end int) (int, int) {
+	for start < end && strings.ContainsRune(" \t\n", rune(s[start])) {
+		start++
+	}
+	for end > start && strings.ContainsRune(" \t\n", rune(s[end-1])) {
+		end--
+	}
+	return start, end
+}
+
+func inRanges(offset int, ranges [][2]int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
 }
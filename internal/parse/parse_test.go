@@ -164,11 +164,11 @@ Right side
 
 				// Slide 1 (index 1): should have column layout.
 				slide1 := p.Slides[1]
-				if slide1.Layout == nil {
-					t.Fatal("slide 1 should have a column layout")
+				if slide1.Grid == nil || len(slide1.Grid.Rows) != 1 {
+					t.Fatal("slide 1 should have a one-row column layout")
 				}
-				if !intSliceEqual(slide1.Layout.Ratios, []int{1, 1}) {
-					t.Errorf("slide 1 layout ratios = %v, want [1, 1]", slide1.Layout.Ratios)
+				if !intSliceEqual(slide1.Grid.Rows[0].Ratios, []int{1, 1}) {
+					t.Errorf("slide 1 layout ratios = %v, want [1, 1]", slide1.Grid.Rows[0].Ratios)
 				}
 
 				// Columns should be populated
@@ -196,7 +196,7 @@ Right side
 
 				// Slide 2 (index 2): full width, no layout, no columns.
 				slide2 := p.Slides[2]
-				if slide2.Layout != nil {
+				if slide2.Grid != nil {
 					t.Errorf("slide 2 should not have a layout")
 				}
 				if len(slide2.Columns) != 0 {
@@ -261,3 +261,137 @@ No frontmatter here.`,
 		})
 	}
 }
+
+func TestParsePresentationSpans(t *testing.T) {
+	input := "---\r\n" +
+		"author: Spans\r\n" +
+		"---\r\n" +
+		"# One\r\n" +
+		"<!-- pause -->\r\n" +
+		"Reveal\r\n" +
+		"---\r\n" +
+		"<!-- column_layout: [1, 1] -->\r\n" +
+		"<!-- column: 0 -->\r\n" +
+		"Left\r\n" +
+		"<!-- column: 1 -->\r\n" +
+		"```go\r\n" +
+		"x := 1\r\n" +
+		"```\r\n" +
+		"<!-- speaker_note: Note -->\r\n"
+
+	p := ParsePresentation(input)
+	if len(p.Slides) != 2 {
+		t.Fatalf("slide count = %d, want 2", len(p.Slides))
+	}
+
+	normalized := strings.ReplaceAll(input, "\r\n", "\n")
+	text := func(s model.Span) string { return normalized[s.Start:s.End] }
+
+	s0 := p.Slides[0]
+	if s0.Span.StartLine != 4 || s0.Span.EndLine != 6 {
+		t.Errorf("slide 0 lines = %d-%d, want 4-6", s0.Span.StartLine, s0.Span.EndLine)
+	}
+	if got := text(s0.Span); got != "# One\n<!-- pause -->\nReveal" {
+		t.Errorf("slide 0 text = %q", got)
+	}
+	if len(s0.Chunks) != 2 {
+		t.Fatalf("slide 0 chunks = %d, want 2", len(s0.Chunks))
+	}
+	if s0.Chunks[0].Span.StartLine != 4 || s0.Chunks[1].Span.EndLine != 6 {
+		t.Errorf("chunk lines = %d.., ..%d, want 4.., ..6",
+			s0.Chunks[0].Span.StartLine, s0.Chunks[1].Span.EndLine)
+	}
+	if got := text(s0.Chunks[1].Span); !strings.Contains(got, "Reveal") {
+		t.Errorf("chunk 1 text = %q, want it to contain Reveal", got)
+	}
+
+	s1 := p.Slides[1]
+	if s1.Span.StartLine != 8 || s1.Span.EndLine != 15 {
+		t.Errorf("slide 1 lines = %d-%d, want 8-15", s1.Span.StartLine, s1.Span.EndLine)
+	}
+	if len(s1.ColumnSpans) != 2 {
+		t.Fatalf("column spans = %d, want 2", len(s1.ColumnSpans))
+	}
+	if got := text(s1.ColumnSpans[0]); !strings.Contains(got, "Left") {
+		t.Errorf("column 0 text = %q, want it to contain Left", got)
+	}
+	if s1.ColumnSpans[1].EndLine != 15 {
+		t.Errorf("column 1 ends on line %d, want 15", s1.ColumnSpans[1].EndLine)
+	}
+	if len(s1.NoteSpans) != 1 || s1.NoteSpans[0].StartLine != 15 {
+		t.Fatalf("note spans = %+v, want one on line 15", s1.NoteSpans)
+	}
+	if got := text(s1.NoteSpans[0]); got != "<!-- speaker_note: Note -->" {
+		t.Errorf("note text = %q", got)
+	}
+	if len(s1.CodeBlocks) != 1 {
+		t.Fatalf("code blocks = %d, want 1", len(s1.CodeBlocks))
+	}
+	if cb := s1.CodeBlocks[0]; cb.Language != "go" {
+		t.Errorf("code block language = %q, want go", cb.Language)
+	}
+	if cs := s1.CodeBlocks[0].Span; cs.StartLine != 12 || cs.EndLine != 14 {
+		t.Errorf("code block lines = %d-%d, want 12-14", cs.StartLine, cs.EndLine)
+	}
+	if got := text(s1.CodeBlocks[0].Span); got != "```go\nx := 1\n```" {
+		t.Errorf("code block text = %q", got)
+	}
+}
+
+func TestParseSplitOptions(t *testing.T) {
+	t.Run("slide_level", func(t *testing.T) {
+		p := ParsePresentation("---\nslide_level: 2\n---\n# Notes\n\n## One\n\nText\n\n## Two\n")
+		if len(p.Slides) != 3 {
+			t.Fatalf("len(slides) = %d, want 3", len(p.Slides))
+		}
+		if got := p.Slides[2].Title(); got != "Two" {
+			t.Errorf("slide 2 title = %q, want Two", got)
+		}
+		if got := p.Slides[1].Span.StartLine; got != 6 {
+			t.Errorf("slide 1 starts on line %d, want 6", got)
+		}
+	})
+
+	t.Run("separator", func(t *testing.T) {
+		p := ParsePresentation("---\nseparator: '<!-- end_slide -->'\n---\n# One\n<!-- end_slide -->\n# Two\n")
+		if len(p.Slides) != 2 || len(p.Diagnostics) != 0 {
+			t.Fatalf("slides = %d, diagnostics = %v; want 2 slides and none", len(p.Slides), diagCodes(p))
+		}
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		wantLine int
+		wantMsg  string
+	}{
+		{
+			name:     "invalid separator",
+			input:    "---\nauthor: Me\nseparator: '(('\n---\n# One\n---\n# Two",
+			wantLine: 3,
+			wantMsg:  "separator is not a valid regular expression",
+		},
+		{
+			name:     "slide_level out of range",
+			input:    "---\nslide_level: 7\n---\n# One\n---\n# Two",
+			wantLine: 2,
+			wantMsg:  "slide_level must be between 1 and 6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ParsePresentation(tt.input)
+			if len(p.Diagnostics) != 1 {
+				t.Fatalf("diagnostics = %v, want one", diagCodes(p))
+			}
+			d := p.Diagnostics[0]
+			if d.Code != "frontmatter-value" || d.Span.StartLine != tt.wantLine || !strings.Contains(d.Message, tt.wantMsg) {
+				t.Errorf("diagnostic = %s line %d %q, want frontmatter-value line %d containing %q",
+					d.Code, d.Span.StartLine, d.Message, tt.wantLine, tt.wantMsg)
+			}
+			if len(p.Slides) != 2 {
+				t.Errorf("len(slides) = %d, want 2 (falls back to ---)", len(p.Slides))
+			}
+		})
+	}
+}
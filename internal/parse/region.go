@@ -0,0 +1,158 @@
+package parse
+
+import (
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// region is a part of a slide between an opening comment, such as
+// <!-- only: internal -->, and its closing one. A region left open runs to
+// the end of the slide.
+type region struct {
+	open  CommandWithPosition
+	inner [2]int // the text between the comments
+}
+
+// regions finds the regions of raw, outside code, opened by open commands
+// and closed by close commands named name and end_name. Unmatched and
+// nested comments are reported and ignored.
+func (p *parser) regions(raw string, base int, open, close model.CommandType, name string) []region {
+	cmds, _ := extractCommands(raw, func(inner string) bool {
+		cmd, ok := parseCommand(inner)
+		return ok && (cmd.Type == open || cmd.Type == close)
+	})
+
+	var found []region
+	var cur *CommandWithPosition
+	for i, cmd := range cmds {
+		switch {
+		case cmd.Command.Type == open && cur != nil:
+			p.report(model.SeverityWarning, "unmatched-"+name, base+cmd.Start, base+cmd.End,
+				"%s regions cannot be nested; close the region above with <!-- end_%s --> first", name, name)
+		case cmd.Command.Type == open:
+			cur = &cmds[i]
+		case cur == nil:
+			p.report(model.SeverityWarning, "unmatched-"+name, base+cmd.Start, base+cmd.End,
+				"end_%s comment without a %s comment before it", name, name)
+		default:
+			found = append(found, region{open: *cur, inner: [2]int{cur.End, cmd.Start}})
+			cur = nil
+		}
+	}
+	if cur != nil {
+		p.report(model.SeverityWarning, "unmatched-"+name, base+cur.Start, base+cur.End,
+			"%s region is never closed; add <!-- end_%s --> where it ends", name, name)
+		found = append(found, region{open: *cur, inner: [2]int{cur.End, len(raw)}})
+	}
+	return found
+}
+
+// blanker blanks ranges of a slide, keeping its newlines so offsets and
+// line numbers stay valid, as notes blocks are removed.
+type blanker []byte
+
+// blank replaces the text in [start, end) with spaces.
+func (b blanker) blank(start, end int) {
+	for i := start; i < end; i++ {
+		if b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+}
+
+// cutter collects the ranges of a slide to remove, such as the regions
+// meant for other profiles or languages and their comments. The text is
+// cut rather than blanked, so what follows a comment at the start of a
+// line is not indented into a code block, and a line that held only a
+// comment does not leave a blank line behind.
+type cutter struct {
+	raw    string
+	ranges [][2]int
+}
+
+// cut marks the text in [start, end) for removal.
+func (c *cutter) cut(start, end int) {
+	if start < end {
+		c.ranges = append(c.ranges, [2]int{start, end})
+	}
+}
+
+// cutComments marks every comment of the slide, outside code, whose inner
+// text passes match, so nested and stray region comments never reach the
+// slide.
+func (c *cutter) cutComments(match func(inner string) bool) {
+	cmds, _ := extractCommands(c.raw, match)
+	for _, cmd := range cmds {
+		c.cut(cmd.Start, cmd.End)
+	}
+}
+
+// lines returns the marked ranges merged and in order, each widened to
+// whole lines, newline included, where the cut leaves nothing else on
+// them.
+func (c *cutter) lines() [][2]int {
+	covered := make([]bool, len(c.raw))
+	for _, r := range c.ranges {
+		for i := r[0]; i < r[1]; i++ {
+			covered[i] = true
+		}
+	}
+	for start := 0; start < len(c.raw); {
+		end := strings.IndexByte(c.raw[start:], '\n')
+		if end < 0 {
+			end = len(c.raw)
+		} else {
+			end += start
+		}
+		touched, kept := false, false
+		for i := start; i < end; i++ {
+			if covered[i] {
+				touched = true
+			} else if c.raw[i] != ' ' && c.raw[i] != '\t' {
+				kept = true
+			}
+		}
+		if touched && !kept {
+			for i := start; i < min(end+1, len(c.raw)); i++ {
+				covered[i] = true
+			}
+		}
+		start = end + 1
+	}
+
+	var ranges [][2]int
+	for i := 0; i < len(covered); i++ {
+		if !covered[i] {
+			continue
+		}
+		start := i
+		for i < len(covered) && covered[i] {
+			i++
+		}
+		ranges = append(ranges, [2]int{start, i})
+	}
+	return ranges
+}
+
+// apply removes the marked text from the slide, which starts at offset
+// base of the parsed text, and from the parsed text itself, so offsets
+// into the slide still map to the lines they came from. It returns the
+// slide's remaining text.
+func (c *cutter) apply(src *sourceMap, base int) string {
+	ranges := c.lines()
+	if len(ranges) == 0 {
+		return c.raw
+	}
+	var b strings.Builder
+	cuts := make([][2]int, len(ranges))
+	last := 0
+	for i, r := range ranges {
+		b.WriteString(c.raw[last:r[0]])
+		last = r[1]
+		cuts[i] = [2]int{base + r[0], base + r[1]}
+	}
+	b.WriteString(c.raw[last:])
+	src.cut(cuts)
+	return b.String()
+}
@@ -1,16 +1,105 @@
 package parse
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
-// SplitSlides splits raw content into individual slide strings using \n---\n delimiter.
-func SplitSlides(content string) []string {
-	// Normalize line endings
-	content = strings.ReplaceAll(content, "\r\n", "\n")
+// rawSlide is the unparsed text of one slide and its byte offset in the
+// content it was split from.
+type rawSlide struct {
+	text  string
+	start int
+}
+
+// splitOptions changes where splitSlides starts new slides.
+type splitOptions struct {
+	// Separator is a regular expression that a whole line must match to
+	// separate slides, replacing the default ---.
+	Separator string
+
+	// SlideLevel, when between 1 and 6, also starts a new slide at every
+	// ATX heading of that level or higher, as pandoc does.
+	SlideLevel int
+}
 
-	slides := strings.Split(content, "\n---\n")
+// splitRule is the compiled form of splitOptions.
+type splitRule struct {
+	separator  *regexp.Regexp // nil splits at ---
+	slideLevel int            // 0 disables heading splits
+}
+
+func newSplitRule(opts splitOptions) (splitRule, error) {
+	rule := splitRule{slideLevel: opts.SlideLevel}
+	if opts.Separator != "" {
+		// Compile the expression alone first so errors quote it as written.
+		if _, err := regexp.Compile(opts.Separator); err != nil {
+			return splitRule{}, err
+		}
+		rule.separator = regexp.MustCompile(`^(?:` + opts.Separator + `)$`)
+	}
+	return rule, nil
+}
+
+// headingLevel returns the level of an ATX heading line, or 0.
+func headingLevel(line string) int {
+	if !atxHeadingRegex.MatchString(line) {
+		return 0
+	}
+	return strings.Count(strings.Fields(line)[0], "#")
+}
+
+// splitSlides splits content, whose line endings are already normalized,
+// into slides at lines consisting solely of --- or, with a custom
+// separator, at lines matching it, and at headings up to the rule's slide
+// level. Delimiters inside fenced code, HTML blocks and indented code are
+// treated as content. Each slide keeps its offset in content.
+func splitSlides(content string, rule splitRule) []rawSlide {
+	var slides []rawSlide
+	var scanner blockScanner
+	start, offset := 0, 0
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lineStart := offset
+		offset += len(line) + 1
+
+		// A custom separator may itself look like an HTML block, such as
+		// <!-- end_slide -->, so it is matched before the line is scanned.
+		if rule.separator != nil && !scanner.inBlock() && rule.separator.MatchString(line) {
+			if i > 0 {
+				end := max(start, lineStart-1)
+				slides = append(slides, rawSlide{text: content[start:end], start: start})
+			}
+			start = min(offset, len(content))
+			scanner = blockScanner{}
+			continue
+		}
+
+		if scanner.literal(line) {
+			continue
+		}
+		// A delimiter needs a newline on both sides, so the first and
+		// last lines never split.
+		if rule.separator == nil && line == "---" && i > 0 && i < len(lines)-1 {
+			// A delimiter right after another leaves an empty slide.
+			end := max(start, lineStart-1)
+			slides = append(slides, rawSlide{text: content[start:end], start: start})
+			start = offset
+			continue
+		}
+		// A heading starts a slide of its own, unless everything before it
+		// in the current slide is blank.
+		if level := headingLevel(line); level > 0 && level <= rule.slideLevel {
+			if strings.TrimSpace(content[start:lineStart]) != "" {
+				slides = append(slides, rawSlide{text: content[start : lineStart-1], start: start})
+			}
+			start = lineStart
+		}
+	}
+	slides = append(slides, rawSlide{text: content[start:], start: start})
 
 	// Remove empty trailing slide if present
-	if len(slides) > 0 && strings.TrimSpace(slides[len(slides)-1]) == "" {
+	if len(slides) > 0 && strings.TrimSpace(slides[len(slides)-1].text) == "" {
 		slides = slides[:len(slides)-1]
 	}
 
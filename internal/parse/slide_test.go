@@ -1,10 +1,22 @@
 package parse
 
 import (
+	"strings"
 	"testing"
 )
 
-func TestSplitSlides(t *testing.T) {
+// slideTexts parses content and returns the source text of each slide.
+func slideTexts(content string) []string {
+	pres := ParsePresentation(content)
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	texts := []string{}
+	for _, s := range pres.Slides {
+		texts = append(texts, content[s.Span.Start:s.Span.End])
+	}
+	return texts
+}
+
+func TestParseSplitsSlides(t *testing.T) {
 	tests := []struct {
 		name  string
 		input string
@@ -41,12 +53,49 @@ func TestSplitSlides(t *testing.T) {
 			want:  []string{"# Slide 1", "# Slide 2"},
 		},
 		{
-			// The --- delimiter inside a fenced code block is NOT special-cased:
-			// SplitSlides splits on any \n---\n occurrence, so code blocks
-			// containing --- will be treated as a slide delimiter.
-			name:  "delimiter inside code block splits (known behavior)",
+			name:  "delimiter inside code block is content",
 			input: "# Slide 1\n\n```\nsome\n---\ncode\n```",
-			want:  []string{"# Slide 1\n\n```\nsome", "code\n```"},
+			want:  []string{"# Slide 1\n\n```\nsome\n---\ncode\n```"},
+		},
+		{
+			name:  "delimiter inside yaml fence then real delimiter",
+			input: "# Manifest\n\n```yaml\na: 1\n---\nb: 2\n```\n---\n# Next",
+			want:  []string{"# Manifest\n\n```yaml\na: 1\n---\nb: 2\n```", "# Next"},
+		},
+		{
+			name:  "delimiter inside tilde fence is content",
+			input: "~~~diff\n---\n+++\n~~~\n---\n# Next",
+			want:  []string{"~~~diff\n---\n+++\n~~~", "# Next"},
+		},
+		{
+			name:  "shorter fence does not close longer fence",
+			input: "````\n```\n---\n```\n````\n---\n# Next",
+			want:  []string{"````\n```\n---\n```\n````", "# Next"},
+		},
+		{
+			name:  "unclosed fence runs to end of content",
+			input: "# Slide 1\n```\n---\n# Not a slide",
+			want:  []string{"# Slide 1\n```\n---\n# Not a slide"},
+		},
+		{
+			name:  "delimiter inside multi-line html comment is content",
+			input: "# Slide 1\n<!--\n---\n-->\n---\n# Slide 2",
+			want:  []string{"# Slide 1\n<!--\n---\n-->", "# Slide 2"},
+		},
+		{
+			name:  "delimiter inside html block is content until blank line",
+			input: "<div>\n---\n</div>\n\n---\n# Slide 2",
+			want:  []string{"<div>\n---\n</div>\n", "# Slide 2"},
+		},
+		{
+			name:  "fence inside indented code does not swallow delimiter",
+			input: "Example:\n\n    ```\n---\n# Slide 2",
+			want:  []string{"Example:\n\n    ```", "# Slide 2"},
+		},
+		{
+			name:  "single-line command comment does not open a block",
+			input: "# Slide 1\n<!-- pause -->\nMore\n---\n# Slide 2",
+			want:  []string{"# Slide 1\n<!-- pause -->\nMore", "# Slide 2"},
 		},
 		{
 			// Empty string splits to [""], but then the empty-trailing-slide
@@ -60,6 +109,11 @@ func TestSplitSlides(t *testing.T) {
 			input: "\n---\n",
 			want:  []string{""},
 		},
+		{
+			name:  "adjacent delimiters leave an empty slide",
+			input: "a\n---\n---\nb",
+			want:  []string{"a", "", "b"},
+		},
 		{
 			name:  "delimiter must have newlines on both sides",
 			input: "hello---world",
@@ -69,8 +123,81 @@ func TestSplitSlides(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := SplitSlides(tt.input)
+			got := slideTexts(tt.input)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(slides) = %d, want %d\ngot:  %q\nwant: %q",
+					len(got), len(tt.want), got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("slide[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSplitsSlidesWithOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		frontmatter string
+		want        []string
+	}{
+		{
+			name:        "custom separator replaces ---",
+			input:       "# One\n***\n# Two\n---\nstill two",
+			frontmatter: `separator: '\*\*\*'`,
+			want:        []string{"# One", "# Two\n---\nstill two"},
+		},
+		{
+			name:        "comment separator",
+			input:       "# One\n<!-- end_slide -->\n# Two\n<!-- end_slide -->\n",
+			frontmatter: `separator: '<!-- end_slide -->'`,
+			want:        []string{"# One", "# Two"},
+		},
+		{
+			name:        "adjacent comment separators leave an empty slide",
+			input:       "# One\n<!-- end_slide -->\n<!-- end_slide -->\n# Two",
+			frontmatter: `separator: '<!-- end_slide -->'`,
+			want:        []string{"# One", "", "# Two"},
+		},
+		{
+			name:        "separator must match the whole line",
+			input:       "# One\ntext *** more\n# Still one",
+			frontmatter: `separator: '\*\*\*'`,
+			want:        []string{"# One\ntext *** more\n# Still one"},
+		},
+		{
+			name:        "separator inside fenced code is content",
+			input:       "# One\n```\n***\n```\n***\n# Two",
+			frontmatter: `separator: '\*\*\*'`,
+			want:        []string{"# One\n```\n***\n```", "# Two"},
+		},
+		{
+			name:        "slide level splits at headings",
+			input:       "# Part\n\nIntro\n\n## First\n\nBody\n\n### Detail\n\n## Second",
+			frontmatter: "slide_level: 2",
+			want:        []string{"# Part\n\nIntro\n", "## First\n\nBody\n\n### Detail\n", "## Second"},
+		},
+		{
+			name:        "slide level keeps --- and skips blank slides",
+			input:       "Preamble\n---\n\n## First\n---\n## Second",
+			frontmatter: "slide_level: 2",
+			want:        []string{"Preamble", "## First", "## Second"},
+		},
+		{
+			name:        "slide level ignores headings in code",
+			input:       "## One\n```sh\n# comment\n```\n    # indented\n",
+			frontmatter: "slide_level: 1",
+			want:        []string{"## One\n```sh\n# comment\n```\n    # indented\n"},
+		},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slideTexts("---\n" + tt.frontmatter + "\n---\n" + tt.input)
 			if len(got) != len(tt.want) {
 				t.Fatalf("len(slides) = %d, want %d\ngot:  %q\nwant: %q",
 					len(got), len(tt.want), got, tt.want)
@@ -0,0 +1,203 @@
+package parse
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// sourceMap converts byte offsets in the text being parsed — which may be
+// stitched together from several files by include directives — into spans
+// that point at the file each byte came from.
+type sourceMap struct {
+	text     strings.Builder
+	segments []segment
+	lines    map[string][]int // offset of the first byte of each line, per file
+	sizes    map[string]int   // length of each file in bytes
+}
+
+// segment is a stretch of the parsed text copied from one file. It runs
+// until the next segment starts.
+type segment struct {
+	start  int    // offset in the parsed text
+	file   string // file the stretch was copied from
+	offset int    // offset in file corresponding to start
+}
+
+func newSourceMap() *sourceMap {
+	return &sourceMap{
+		lines: make(map[string][]int),
+		sizes: make(map[string]int),
+	}
+}
+
+// addFile registers the content of file so offsets into it can be turned
+// into line numbers.
+func (m *sourceMap) addFile(file, content string) {
+	starts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	m.lines[file] = starts
+	m.sizes[file] = len(content)
+}
+
+// write appends text, which starts at offset in the registered file, to
+// the parsed text.
+func (m *sourceMap) write(file string, offset int, text string) {
+	if text == "" {
+		return
+	}
+	m.segments = append(m.segments, segment{start: m.text.Len(), file: file, offset: offset})
+	m.text.WriteString(text)
+}
+
+// String returns the parsed text assembled so far.
+func (m *sourceMap) String() string {
+	return m.text.String()
+}
+
+// size returns the length of the parsed text.
+func (m *sourceMap) size() int {
+	return m.text.Len()
+}
+
+// cut removes ranges, which are sorted and do not overlap, from the parsed
+// text. The text after each range moves back to take its place and still
+// maps to the file it came from.
+func (m *sourceMap) cut(ranges [][2]int) {
+	text := m.text.String()
+	for i := len(ranges) - 1; i >= 0; i-- {
+		start, end := ranges[i][0], ranges[i][1]
+		file, offset := m.locate(end)
+		first := sort.Search(len(m.segments), func(j int) bool {
+			return m.segments[j].start >= start
+		})
+		rest := sort.Search(len(m.segments), func(j int) bool {
+			return m.segments[j].start > end
+		})
+
+		segments := append([]segment(nil), m.segments[:first]...)
+		if end < len(text) {
+			segments = append(segments, segment{start: start, file: file, offset: offset})
+		}
+		for _, seg := range m.segments[rest:] {
+			seg.start -= end - start
+			segments = append(segments, seg)
+		}
+		m.segments = segments
+		text = text[:start] + text[end:]
+	}
+	m.text.Reset()
+	m.text.WriteString(text)
+}
+
+// segmentAt returns the index of the segment containing offset.
+func (m *sourceMap) segmentAt(offset int) int {
+	i := sort.Search(len(m.segments), func(i int) bool {
+		return m.segments[i].start > offset
+	})
+	return max(i-1, 0)
+}
+
+// locate maps an offset in the parsed text to a file and an offset in it.
+func (m *sourceMap) locate(offset int) (string, int) {
+	if len(m.segments) == 0 {
+		return "", offset
+	}
+	seg := m.segments[m.segmentAt(offset)]
+	return seg.file, seg.offset + offset - seg.start
+}
+
+// line returns the 1-based line of file containing offset.
+func (m *sourceMap) line(file string, offset int) int {
+	starts := m.lines[file]
+	return max(sort.Search(len(starts), func(i int) bool {
+		return starts[i] > offset
+	}), 1)
+}
+
+// span builds a Span for the half-open range [start, end) of the parsed
+// text. A range that crosses into an included file is clamped to the part
+// that belongs to the file it starts in. An empty range reports the line
+// it sits on.
+func (m *sourceMap) span(start, end int) model.Span {
+	file, fileStart := m.locate(start)
+	if end <= start {
+		return m.fileSpan(file, fileStart, fileStart)
+	}
+
+	last := end - 1
+	i := m.segmentAt(last)
+	for i > 0 && m.segments[i].file != file {
+		last = m.segments[i].start - 1
+		i--
+	}
+	_, fileLast := m.locate(last)
+	return m.fileSpan(file, fileStart, fileLast+1)
+}
+
+// fileSpan builds a Span for the range [start, end) of file itself.
+func (m *sourceMap) fileSpan(file string, start, end int) model.Span {
+	return model.Span{
+		File:      file,
+		StartLine: m.line(file, start),
+		EndLine:   m.line(file, max(end-1, start)),
+		Start:     start,
+		End:       end,
+	}
+}
+
+// lineSpan returns the span of the 1-based line of file, excluding its
+// newline. Lines past the end clamp to the last line.
+func (m *sourceMap) lineSpan(file string, line int) model.Span {
+	starts := m.lines[file]
+	if len(starts) == 0 {
+		return model.Span{File: file}
+	}
+	line = min(max(line, 1), len(starts))
+	end := m.sizes[file]
+	if line < len(starts) {
+		end = starts[line] - 1
+	}
+	return m.fileSpan(file, starts[line-1], end)
+}
+
+// codeBlockRanges returns the byte ranges of the fenced code blocks in
+// content, fences included. An unclosed fence runs to the end of content.
+func codeBlockRanges(content string) [][2]int {
+	var ranges [][2]int
+	var scanner blockScanner
+	open := -1
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		wasFenced := scanner.fence != ""
+		scanner.literal(strings.TrimSuffix(line, "\n"))
+		switch {
+		case !wasFenced && scanner.fence != "":
+			open = offset
+		case wasFenced && scanner.fence == "":
+			ranges = append(ranges, [2]int{open, offset + len(strings.TrimSuffix(line, "\n"))})
+			open = -1
+		}
+		offset += len(line)
+	}
+	if open >= 0 {
+		ranges = append(ranges, [2]int{open, len(content)})
+	}
+	return ranges
+}
+
+// fenceLanguage returns the first word of a code block's info string.
+func fenceLanguage(block string) string {
+	first, _, _ := strings.Cut(block, "\n")
+	info := strings.TrimLeft(strings.TrimSpace(first), "`~")
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,312 @@
+package parse
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// tableAligns maps a table directive's align values to the markdown
+// delimiter row cells that set them.
+var tableAligns = map[string]string{
+	"left":   ":--",
+	"right":  "--:",
+	"center": ":-:",
+}
+
+// tableSpec is a parsed table directive.
+type tableSpec struct {
+	path    string
+	columns []string          // selected columns in order; nil for all
+	sort    string            // column to sort by; "" keeps file order
+	desc    bool              // sort largest first
+	limit   int               // maximum rows; 0 for all
+	format  map[string]string // printf verb per column; "" for every numeric column
+	align   map[string]string // alignment per column; "" for every column
+}
+
+// table writes a markdown table built from the CSV or TSV file named by a
+// table directive, relative to from. A path with spaces is written in
+// double quotes. Options follow the path as key=value
+// pairs: columns=a,b picks and orders columns by header name, sort=col or
+// sort=-col orders rows (numerically when both values are numbers), limit=n
+// keeps the first n rows, format=%.1f or format=col:%.1f,... formats
+// numbers, and align=right or align=col:right,... aligns columns, which
+// otherwise sit right when numeric and left when not. The file becomes a
+// source of the deck so edits to it reload the slide. Failures are
+// reported at the directive and leave an empty line behind.
+func (p *parser) table(from, value string, directive model.Span) {
+	spec, err := parseTableSpec(value)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "table-invalid", directive, "table %s: %v", value, err)
+		return
+	}
+	resolved := spec.path
+	if !filepath.IsAbs(spec.path) {
+		resolved = filepath.Join(p.baseDir(from), spec.path)
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "table-missing", directive,
+			"cannot read %s: %v", spec.path, unwrapPathError(err))
+		p.addMissing(resolved)
+		return
+	}
+	p.addSource(resolved)
+
+	rows, err := buildTable(string(data), filepath.Ext(resolved), spec)
+	if err != nil {
+		p.reportSpan(model.SeverityError, "table-invalid", directive, "table %s: %v", spec.path, err)
+		return
+	}
+	// Every row maps to the directive, which produced it.
+	for i, row := range rows {
+		if i < len(rows)-1 {
+			row += "\n"
+		}
+		p.src.write(from, directive.Start, row)
+	}
+}
+
+// parseTableSpec parses a table directive's path, which may be double
+// quoted, and options.
+func parseTableSpec(value string) (tableSpec, error) {
+	var spec tableSpec
+	fields := strings.Fields(value)
+	if strings.HasPrefix(value, `"`) {
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return spec, fmt.Errorf("path %s is not properly quoted", value)
+		}
+		rest := value[len(quoted):]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			return spec, fmt.Errorf("path %s must be followed by a space", quoted)
+		}
+		path, _ := strconv.Unquote(quoted)
+		fields = append([]string{path}, strings.Fields(rest)...)
+	}
+	if len(fields) == 0 || fields[0] == "" {
+		return spec, fmt.Errorf("the path is empty")
+	}
+	spec.path = fields[0]
+	for _, field := range fields[1:] {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok || val == "" {
+			return spec, fmt.Errorf("option %q is not key=value", field)
+		}
+		switch key {
+		case "columns":
+			spec.columns = strings.Split(val, ",")
+		case "sort":
+			spec.sort, spec.desc = strings.TrimPrefix(val, "-"), strings.HasPrefix(val, "-")
+		case "limit":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				return spec, fmt.Errorf("limit %q is not a positive number", val)
+			}
+			spec.limit = n
+		case "format":
+			formats, err := perColumn(val, func(v string) bool {
+				return !strings.Contains(fmt.Sprintf(v, 1.0), "%!")
+			})
+			if err != nil {
+				return spec, fmt.Errorf("format %v", err)
+			}
+			spec.format = formats
+		case "align":
+			aligns, err := perColumn(val, func(v string) bool { return tableAligns[v] != "" })
+			if err != nil {
+				return spec, fmt.Errorf("align %v; use left, right or center", err)
+			}
+			spec.align = aligns
+		default:
+			return spec, fmt.Errorf("unknown option %q; use columns, sort, limit, format or align", key)
+		}
+	}
+	return spec, nil
+}
+
+// perColumn parses an option that is either one value for every column or
+// a comma-separated list of column:value pairs, checking each value.
+func perColumn(s string, valid func(string) bool) (map[string]string, error) {
+	values := make(map[string]string)
+	if !strings.Contains(s, ":") {
+		if !valid(s) {
+			return nil, fmt.Errorf("%q is not valid", s)
+		}
+		values[""] = s
+		return values, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		col, v, ok := strings.Cut(pair, ":")
+		if !ok || col == "" || !valid(v) {
+			return nil, fmt.Errorf("%q is not valid", pair)
+		}
+		values[col] = v
+	}
+	return values, nil
+}
+
+// buildTable reads CSV data, or TSV when ext is .tsv, whose first record
+// is the header, and returns the lines of the markdown table spec asks for.
+func buildTable(data, ext string, spec tableSpec) ([]string, error) {
+	// A byte order mark would otherwise stick to the first column's name.
+	data = strings.TrimPrefix(data, "\ufeff")
+	r := csv.NewReader(strings.NewReader(data))
+	if strings.EqualFold(ext, ".tsv") {
+		r.Comma = '\t'
+		r.LazyQuotes = true
+	}
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	header, body := records[0], records[1:]
+
+	index := func(name string) (int, error) {
+		if i := slices.Index(header, name); i >= 0 {
+			return i, nil
+		}
+		return 0, fmt.Errorf("no column %q; the columns are %s", name, strings.Join(header, ", "))
+	}
+	var cols []int
+	for i := range header {
+		cols = append(cols, i)
+	}
+	if spec.columns != nil {
+		cols = cols[:0]
+		for _, name := range spec.columns {
+			i, err := index(name)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, i)
+		}
+	}
+	for _, m := range []map[string]string{spec.format, spec.align} {
+		for name := range m {
+			if _, err := index(name); name != "" && err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cell := func(row []string, i int) string {
+		if i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	if spec.sort != "" {
+		key, err := index(spec.sort)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(body, func(a, b []string) int {
+			c := compareCells(cell(a, key), cell(b, key))
+			if spec.desc {
+				return -c
+			}
+			return c
+		})
+	}
+	if spec.limit > 0 && len(body) > spec.limit {
+		body = body[:spec.limit]
+	}
+
+	// A column is numeric when it has numbers and nothing else but empty
+	// cells.
+	numeric := make(map[int]bool)
+	for _, i := range cols {
+		numbers := 0
+		for _, row := range body {
+			text := cell(row, i)
+			if text == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				numbers = 0
+				break
+			}
+			numbers++
+		}
+		numeric[i] = numbers > 0
+	}
+
+	var names, delims []string
+	for _, i := range cols {
+		names = append(names, escapeCell(header[i]))
+		align := columnOption(spec.align, header[i])
+		if align == "" {
+			align = "left"
+			if numeric[i] {
+				align = "right"
+			}
+		}
+		delims = append(delims, tableAligns[align])
+	}
+	lines := []string{tableRow(names), tableRow(delims)}
+	for _, row := range body {
+		var cells []string
+		for _, i := range cols {
+			text := cell(row, i)
+			if format := columnOption(spec.format, header[i]); format != "" {
+				if n, err := strconv.ParseFloat(text, 64); err == nil {
+					text = fmt.Sprintf(format, n)
+				}
+			}
+			cells = append(cells, escapeCell(text))
+		}
+		lines = append(lines, tableRow(cells))
+	}
+	return lines, nil
+}
+
+// columnOption returns a per-column option's value for the column named
+// name, falling back to the value for every column.
+func columnOption(values map[string]string, name string) string {
+	if v, ok := values[name]; ok {
+		return v
+	}
+	return values[""]
+}
+
+// compareCells orders two cells numerically when both are numbers, and
+// as text otherwise.
+func compareCells(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// escapeCell escapes the pipes that would end a markdown table cell and
+// joins the lines of a quoted multi-line value, which would end its row.
+func escapeCell(s string) string {
+	s = strings.NewReplacer("\r\n", " ", "\n", " ").Replace(s)
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func tableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
@@ -0,0 +1,155 @@
+package parse
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const latencyCSV = `service,p50,p99,region
+api,12.5,88.25,us
+auth,3,140.5,eu
+"search, beta",40.125,95,us
+billing,7.75,,eu|west
+`
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		file    string
+		want    []string
+	}{
+		{
+			name: "whole file",
+			want: []string{
+				"| service | p50 | p99 | region |",
+				"| :-- | --: | --: | :-- |",
+				"| api | 12.5 | 88.25 | us |",
+				"| auth | 3 | 140.5 | eu |",
+				"| search, beta | 40.125 | 95 | us |",
+				`| billing | 7.75 |  | eu\|west |`,
+			},
+		},
+		{
+			name:    "columns, sort and limit",
+			options: "columns=service,p99 sort=-p99 limit=2",
+			want: []string{
+				"| service | p99 |",
+				"| :-- | --: |",
+				"| auth | 140.5 |",
+				"| search, beta | 95 |",
+			},
+		},
+		{
+			name:    "numeric sort ascending",
+			options: "columns=p50 sort=p50",
+			want:    []string{"| p50 |", "| --: |", "| 3 |", "| 7.75 |", "| 12.5 |", "| 40.125 |"},
+		},
+		{
+			name:    "format and align",
+			options: "columns=service,p50,p99 format=%.1f align=service:center,p99:left limit=1",
+			want:    []string{"| service | p50 | p99 |", "| :-: | --: | :-- |", "| api | 12.5 | 88.2 |"},
+		},
+		{
+			name:    "per-column format",
+			options: "columns=p50,p99 format=p99:%.0fms limit=1",
+			want:    []string{"| p50 | p99 |", "| --: | --: |", "| 12.5 | 88ms |"},
+		},
+		{
+			name: "tsv",
+			file: "latency.tsv",
+			want: []string{"| host | ms |", "| :-- | --: |", "| a b | 4 |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := tt.file
+			if file == "" {
+				file = "latency.csv"
+			}
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"data/latency.csv": latencyCSV,
+				"data/latency.tsv": "host\tms\na b\t4\n",
+				"talk/deck.md":     "# Latency\n\n<!-- table: ../data/" + file + " " + tt.options + " -->\n\nLower is better.\n",
+			})
+
+			pres := parseFile(t, filepath.Join(dir, "talk", "deck.md"))
+			if len(pres.Diagnostics) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+			}
+			content := pres.Slides[0].VisibleContent(0)
+			want := "\n\n" + strings.Join(tt.want, "\n") + "\n\nLower is better."
+			if !strings.Contains(content, want) {
+				t.Errorf("content = %q, want the table\n%s", content, strings.Join(tt.want, "\n"))
+			}
+			if !slices.Contains(pres.Sources, filepath.Join(dir, "data", file)) {
+				t.Errorf("sources = %v, want the data file watched", pres.Sources)
+			}
+		})
+	}
+}
+
+func TestParseTableData(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"team stats.csv": "\ufeffname,notes,score,empty\nAda,\"first\nsecond\",1,\nBob,3,2,\n",
+		"deck.md":        "# Team\n\n<!-- table: \"team stats.csv\" columns=name,notes,score,empty -->\n",
+	})
+
+	pres := parseFile(t, filepath.Join(dir, "deck.md"))
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+	want := []string{
+		"| name | notes | score | empty |",
+		"| :-- | :-- | --: | :-- |",
+		"| Ada | first second | 1 |  |",
+		"| Bob | 3 | 2 |  |",
+	}
+	if content := pres.Slides[0].VisibleContent(0); !strings.Contains(content, strings.Join(want, "\n")) {
+		t.Errorf("content = %q, want the table\n%s", content, strings.Join(want, "\n"))
+	}
+}
+
+func TestParseTableErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		wantCode  string
+	}{
+		{"missing file", "nope.csv", "table-missing"},
+		{"unknown column", "latency.csv columns=p95", "table-invalid"},
+		{"unknown sort column", "latency.csv sort=-p95", "table-invalid"},
+		{"unknown option", "latency.csv top=3", "table-invalid"},
+		{"bad limit", "latency.csv limit=many", "table-invalid"},
+		{"bad format", "latency.csv format=ms", "table-invalid"},
+		{"bad align", "latency.csv align=p50:middle", "table-invalid"},
+		{"malformed csv", "broken.csv", "table-invalid"},
+		{"unclosed quote", `"latency.csv limit=1`, "table-invalid"},
+		{"quoted path run into an option", `"latency.csv"limit=1`, "table-invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"latency.csv": latencyCSV,
+				"broken.csv":  "a,b\n\"unterminated,1\n",
+				"deck.md":     "# One\n\n<!-- table: " + tt.directive + " -->\n",
+			})
+
+			pres := parseFile(t, filepath.Join(dir, "deck.md"))
+			if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != tt.wantCode ||
+				pres.Diagnostics[0].Span.StartLine != 3 {
+				t.Fatalf("diagnostics = %v, want one %s on line 3", pres.Diagnostics, tt.wantCode)
+			}
+			if strings.Contains(pres.Slides[0].VisibleContent(0), "|") {
+				t.Errorf("a failed table should leave nothing behind")
+			}
+		})
+	}
+}
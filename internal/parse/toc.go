@@ -0,0 +1,189 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTOCDepth lists slides titled by # and ## headings.
+const defaultTOCDepth = 2
+
+// tocOptions are the settings of a <!-- toc: {...} --> comment.
+type tocOptions struct {
+	Depth     int  `yaml:"depth"`     // deepest title heading level listed
+	Highlight bool `yaml:"highlight"` // bold the section the slide is in
+}
+
+// isTOCCommand reports whether the inner text of a comment is a toc
+// command.
+func isTOCCommand(inner string) bool {
+	cmd, ok := parseCommand(inner)
+	return ok && cmd.Type == model.CmdTOC
+}
+
+// decodeTOC decodes the options of a toc comment. As in slide comments,
+// the braces of the flow mapping may be omitted.
+func decodeTOC(value string) (tocOptions, error) {
+	opts := tocOptions{Depth: defaultTOCDepth}
+	if value == "" {
+		return opts, nil
+	}
+	if !strings.HasPrefix(value, "{") {
+		value = "{" + value + "}"
+	}
+	dec := yaml.NewDecoder(strings.NewReader(value))
+	dec.KnownFields(true)
+	if err := dec.Decode(&opts); err != nil {
+		msg := strings.TrimPrefix(err.Error(), "yaml: ")
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+			msg = typeErr.Errors[0]
+		}
+		if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+			msg = m[2]
+		}
+		return tocOptions{Depth: defaultTOCDepth}, errors.New(yamlUnknownFieldRegex.ReplaceAllString(msg, "unknown field $1"))
+	}
+	if opts.Depth < 1 || opts.Depth > 6 {
+		return tocOptions{Depth: defaultTOCDepth}, fmt.Errorf("depth must be between 1 and 6, got %d", opts.Depth)
+	}
+	return opts, nil
+}
+
+// tocEntry is a slide listed by a table of contents.
+type tocEntry struct {
+	slide  int
+	title  string
+	level  int
+	target string // anchor to link to; empty when the slide has none
+}
+
+// expandTOCs replaces every toc comment left in the slides' content with a
+// list of the deck's slide titles, linked to their slides. Slides that are
+// hidden, untitled or hold a toc themselves are not listed.
+func (p *parser) expandTOCs(slides []model.Slide) {
+	hasTOC := make([]bool, len(slides))
+	found := false
+	for i, slide := range slides {
+		for _, chunk := range slide.Chunks {
+			if cmds, _ := extractCommands(chunk.Content, isTOCCommand); len(cmds) > 0 {
+				hasTOC[i], found = true, true
+			}
+		}
+	}
+	if !found {
+		return
+	}
+
+	var entries []tocEntry
+	for i, slide := range slides {
+		if hasTOC[i] || slide.Meta.Hidden {
+			continue
+		}
+		title := slide.Title()
+		if title == "" {
+			continue
+		}
+		target := slide.Meta.ID
+		if target == "" && len(slide.Anchors) > 0 {
+			target = slide.Anchors[0]
+		}
+		entries = append(entries, tocEntry{slide: i, title: title, level: slide.TitleLevel(), target: target})
+	}
+
+	for i := range slides {
+		if !hasTOC[i] {
+			continue
+		}
+		slide := &slides[i]
+		listOnly := func(opts tocOptions) string {
+			list, _ := tocList(entries, opts, i)
+			return list
+		}
+		for j := range slide.Chunks {
+			chunk := &slide.Chunks[j]
+			// A grid's cells repeat the chunk's content, so the links are
+			// recorded from the chunk alone.
+			chunk.Content = replaceTOCs(chunk.Content, func(opts tocOptions) string {
+				list, links := tocList(entries, opts, i)
+				for k := range links {
+					links[k].Span = chunk.Span
+				}
+				slide.Links = append(slide.Links, links...)
+				return list
+			})
+			for k := range chunk.Cells {
+				chunk.Cells[k] = replaceTOCs(chunk.Cells[k], listOnly)
+			}
+		}
+		for j := range slide.Columns {
+			slide.Columns[j] = replaceTOCs(slide.Columns[j], listOnly)
+		}
+	}
+}
+
+// replaceTOCs replaces each toc comment in content, outside fenced code,
+// with list(options).
+func replaceTOCs(content string, list func(tocOptions) string) string {
+	cmds, _ := extractCommands(content, isTOCCommand)
+	if len(cmds) == 0 {
+		return content
+	}
+	var b strings.Builder
+	last := 0
+	for _, cmd := range cmds {
+		opts, _ := decodeTOC(cmd.Command.Value) // reported when the slide was parsed
+		b.WriteString(content[last:cmd.Start])
+		b.WriteString(list(opts))
+		last = cmd.End
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// tocList renders the entries down to opts.Depth as a markdown list nested
+// by heading level, for a toc on slide current. With opts.Highlight, the
+// top-level entry at or before current is bold. It also returns the list's
+// links.
+func tocList(entries []tocEntry, opts tocOptions, current int) (string, []model.Link) {
+	var listed []tocEntry
+	top := 0
+	for _, e := range entries {
+		if e.level <= opts.Depth {
+			listed = append(listed, e)
+			if top == 0 || e.level < top {
+				top = e.level
+			}
+		}
+	}
+
+	highlight := -1
+	if opts.Highlight {
+		for i, e := range listed {
+			if e.level == top && e.slide <= current {
+				highlight = i
+			}
+		}
+	}
+
+	var b strings.Builder
+	var links []model.Link
+	indent := -2
+	for i, e := range listed {
+		indent = min(2*(e.level-top), indent+2)
+		item := e.title
+		if e.target != "" {
+			item = "[" + e.title + "](#" + e.target + ")"
+			links = append(links, model.Link{Text: e.title, Target: e.target})
+		}
+		if i == highlight {
+			item = "**" + item + "**"
+		}
+		fmt.Fprintf(&b, "%s- %s\n", strings.Repeat(" ", indent), item)
+	}
+	return b.String(), links
+}
@@ -0,0 +1,86 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+const tocDeck = `# Agenda
+
+<!-- toc -->
+---
+# Part One
+---
+## Details
+
+` + "```markdown\n<!-- toc -->\n# Not a title\n```" + `
+---
+<!-- skip -->
+# Backup
+---
+<!-- slide: {id: two} -->
+# Part Two
+---
+# Where we are
+
+<!-- toc: {depth: 1, highlight: true} -->
+---
+### Too deep
+---
+No title here`
+
+func TestParseTOC(t *testing.T) {
+	pres := ParsePresentation(tocDeck)
+	if len(pres.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", pres.Diagnostics)
+	}
+
+	agenda := pres.Slides[0].VisibleContent(0)
+	want := "- [Part One](#part-one)\n  - [Details](#details)\n- [Part Two](#two)\n"
+	if !strings.Contains(agenda, want) {
+		t.Errorf("agenda = %q, want it to contain %q", agenda, want)
+	}
+	if strings.Contains(agenda, "<!--") {
+		t.Errorf("toc comment left in content: %q", agenda)
+	}
+
+	links := pres.Slides[0].Links
+	if len(links) != 3 || links[2].Target != "two" || links[2].Text != "Part Two" {
+		t.Errorf("links = %+v, want one per entry", links)
+	}
+
+	where := pres.Slides[5].VisibleContent(0)
+	want = "- [Part One](#part-one)\n- **[Part Two](#two)**\n"
+	if !strings.Contains(where, want) {
+		t.Errorf("highlighted toc = %q, want it to contain %q", where, want)
+	}
+
+	if details := pres.Slides[2].VisibleContent(0); !strings.Contains(details, "<!-- toc -->") {
+		t.Errorf("toc in a code block should stay as written, got %q", details)
+	}
+}
+
+func TestParseTOCInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		wantMsg string
+	}{
+		{"depth out of range", "<!-- toc: {depth: 7} -->", "depth must be between 1 and 6"},
+		{"unknown option", "<!-- toc: {levels: 2} -->", "unknown field levels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pres := ParsePresentation("# Agenda\n\n" + tt.comment + "\n---\n# One\n## Two")
+			if len(pres.Diagnostics) != 1 || pres.Diagnostics[0].Code != "invalid-command" ||
+				!strings.Contains(pres.Diagnostics[0].Message, tt.wantMsg) {
+				t.Fatalf("diagnostics = %v, want one invalid-command about %q", pres.Diagnostics, tt.wantMsg)
+			}
+			// The default options still apply.
+			if got := pres.Slides[0].VisibleContent(0); !strings.Contains(got, "- [One](#one)\n") {
+				t.Errorf("content = %q, want the default toc", got)
+			}
+		})
+	}
+}
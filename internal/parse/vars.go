@@ -0,0 +1,105 @@
+package parse
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// inlineCodeRegex matches a single-backtick code span on one line.
+var inlineCodeRegex = regexp.MustCompile("`[^`\n]+`")
+
+// varRegex matches a template reference, {{ .name }} or {{ env "NAME" }},
+// capturing the variable name or the environment variable name.
+var varRegex = regexp.MustCompile(`\{\{-?\s*(?:\.([A-Za-z_][A-Za-z0-9_]*)|env\s+"([^"]*)")\s*-?\}\}`)
+
+// deckVars returns the frontmatter vars with overrides applied on top.
+func deckVars(fm, overrides map[string]string) map[string]string {
+	vars := make(map[string]string, len(fm)+len(overrides))
+	for k, v := range fm {
+		vars[k] = v
+	}
+	for k, v := range overrides {
+		vars[k] = v
+	}
+	return vars
+}
+
+// expandVars replaces references to vars and environment variables in s,
+// outside code blocks and code spans so code is shown as written. A
+// reference to an undefined var is left as written; an unset environment
+// variable expands to "".
+func expandVars(s string, vars map[string]string) string {
+	if len(s) < 4 {
+		return s
+	}
+	code := append(codeBlockRanges(s), inlineCodeSpans(s)...)
+	var b strings.Builder
+	last := 0
+	for _, loc := range varRegex.FindAllStringSubmatchIndex(s, -1) {
+		if inRanges(loc[0], code) {
+			continue
+		}
+		value := s[loc[0]:loc[1]]
+		if loc[2] < 0 {
+			value = os.Getenv(s[loc[4]:loc[5]])
+		} else if v, ok := vars[s[loc[2]:loc[3]]]; ok {
+			value = v
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(value)
+		last = loc[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// inlineCodeSpans returns the ranges of the single-backtick code spans in
+// s.
+func inlineCodeSpans(s string) [][2]int {
+	var spans [][2]int
+	for _, loc := range inlineCodeRegex.FindAllStringIndex(s, -1) {
+		spans = append(spans, [2]int{loc[0], loc[1]})
+	}
+	return spans
+}
+
+// checkVars reports references to undefined vars outside code blocks and
+// code spans.
+func (p *parser) checkVars(raw string, base int, codeRanges [][2]int) {
+	spans := inlineCodeSpans(raw)
+	for _, loc := range varRegex.FindAllStringSubmatchIndex(raw, -1) {
+		if loc[2] < 0 || inRanges(loc[0], codeRanges) || inRanges(loc[0], spans) {
+			continue
+		}
+		name := raw[loc[2]:loc[3]]
+		if _, ok := p.vars[name]; !ok {
+			p.report(model.SeverityWarning, "undefined-var", base+loc[0], base+loc[1],
+				"variable %q is not defined in vars or with --var", name)
+		}
+	}
+}
+
+// expandSlideVars applies expandVars to everything a slide displays.
+func (p *parser) expandSlideVars(slide *model.Slide) {
+	for i := range slide.Chunks {
+		chunk := &slide.Chunks[i]
+		chunk.Content = expandVars(chunk.Content, p.vars)
+		for j := range chunk.Cells {
+			chunk.Cells[j] = expandVars(chunk.Cells[j], p.vars)
+		}
+	}
+	for i := range slide.Columns {
+		slide.Columns[i] = expandVars(slide.Columns[i], p.vars)
+	}
+	for i := range slide.SpeakerNotes {
+		slide.SpeakerNotes[i] = expandVars(slide.SpeakerNotes[i], p.vars)
+	}
+	for i := range slide.Footnotes {
+		slide.Footnotes[i].Text = expandVars(slide.Footnotes[i].Text, p.vars)
+	}
+	slide.Meta.Title = expandVars(slide.Meta.Title, p.vars)
+	slide.Meta.Section = expandVars(slide.Meta.Section, p.vars)
+}
@@ -0,0 +1,95 @@
+package parse
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandVars(t *testing.T) {
+	t.Setenv("DECK_TEST_ROOM", "Hall B")
+	vars := map[string]string{"event": "GopherCon", "year": "2025"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"var", "Welcome to {{ .event }}!", "Welcome to GopherCon!"},
+		{"no spaces", "{{.event}} {{.year}}", "GopherCon 2025"},
+		{"trim markers", "{{- .event -}}", "GopherCon"},
+		{"env", `Room: {{ env "DECK_TEST_ROOM" }}`, "Room: Hall B"},
+		{"unset env", `[{{ env "DECK_TEST_UNSET" }}]`, "[]"},
+		{"undefined var kept", "{{ .Values.image }} {{ .missing }}", "{{ .Values.image }} {{ .missing }}"},
+		{"plain text", "no templates here", "no templates here"},
+		{"code block kept", "```sh\necho {{ .event }} {{ env \"DECK_TEST_ROOM\" }}\n```\n{{ .event }}", "```sh\necho {{ .event }} {{ env \"DECK_TEST_ROOM\" }}\n```\nGopherCon"},
+		{"code span kept", "`{{ .year }}` is {{ .year }}", "`{{ .year }}` is 2025"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandVars(tt.input, vars); got != tt.want {
+				t.Errorf("expandVars(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	input := `---
+footer: "{{ .event }} | {current_slide}"
+vars:
+  event: KubeCon
+  customer: Acme
+---
+<!-- slide: {title: "{{ .customer }} intro"} -->
+# Hello {{ .customer }} at {{ .event }}
+
+<!-- speaker_note: Thank {{ .customer }} -->
+
+` + "```sh\ncurl https://{{ .customer }}.example.com\necho {{ .undefined }}\n```" + `
+---
+Unknown {{ .speaker }}, but ` + "`{{ .example }}`" + ` is code`
+
+	p := Parse(input, Options{Vars: map[string]string{"event": "GopherCon"}})
+
+	if got := p.Frontmatter.Footer; got != "GopherCon | {current_slide}" {
+		t.Errorf("footer = %q, want the --var value expanded", got)
+	}
+	s := p.Slides[0]
+	content := s.Chunks[0].Content
+	if !strings.Contains(content, "# Hello Acme at GopherCon") {
+		t.Errorf("content = %q, want vars expanded with the override applied", content)
+	}
+	if !strings.Contains(content, "curl https://{{ .customer }}.example.com") || !strings.Contains(content, "{{ .undefined }}") {
+		t.Errorf("code block = %q, want the code kept as written", content)
+	}
+	if s.Title() != "Acme intro" {
+		t.Errorf("title = %q, want %q", s.Title(), "Acme intro")
+	}
+	if s.SpeakerNotes[0] != "Thank Acme" {
+		t.Errorf("note = %q, want %q", s.SpeakerNotes[0], "Thank Acme")
+	}
+
+	// Only the reference outside code is reported.
+	if codes := diagCodes(p); len(codes) != 1 || codes[0] != "undefined-var" {
+		t.Fatalf("diagnostics = %v, want one undefined-var", codes)
+	}
+	if d := p.Diagnostics[0]; d.Span.StartLine != 17 || !strings.Contains(d.Message, `"speaker"`) {
+		t.Errorf("diagnostic = %+v, want speaker on line 17", d)
+	}
+}
+
+func TestParseVarsInExcerpt(t *testing.T) {
+	t.Setenv("DECK_TEST_ROOM", "Hall B")
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"deploy.sh": "echo {{ .customer }} {{ env \"DECK_TEST_ROOM\" }}\n",
+		"deck.md":   "---\nvars:\n  customer: Acme\n---\n# {{ .customer }}\n\n<!-- file: deploy.sh -->\n",
+	})
+
+	content := parseFile(t, filepath.Join(dir, "deck.md")).Slides[0].VisibleContent(0)
+	if !strings.Contains(content, "# Acme") || !strings.Contains(content, `echo {{ .customer }} {{ env "DECK_TEST_ROOM" }}`) {
+		t.Errorf("content = %q, want the heading expanded and the excerpt kept as written", content)
+	}
+}
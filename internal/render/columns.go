@@ -7,28 +7,64 @@ import (
 	"github.com/jedwards1230/deck/internal/model"
 )
 
+// columnGap is the space between adjacent cells of a row.
+const columnGap = 2
+
 // RenderColumns renders content in a multi-column layout. Each column's
 // markdown is rendered at its proportional width, then the columns are
-// joined horizontally with equal height padding.
+// joined horizontally with equal height padding. Columns beyond the
+// layout's ratios are ignored.
 func RenderColumns(columns []string, layout model.ColumnLayout, totalWidth int, cache *RendererCache) (string, error) {
-	widths := layout.ColumnWidths(totalWidth)
+	return RenderGrid(layout.Grid(), columns, totalWidth, cache)
+}
+
+// RenderGrid renders a grid layout. Rows are stacked top to bottom; within
+// a row each cell is rendered at its proportional width, nested grids
+// recursively, placed by its alignment and padded to the height of the
+// tallest one before being joined. cells holds the content of each leaf.
+func RenderGrid(grid model.Grid, cells []string, totalWidth int, cache *RendererCache) (string, error) {
+	var rows []string
+	for _, row := range grid.Rows {
+		out, err := renderRow(row, cells, totalWidth, cache)
+		if err != nil {
+			return "", err
+		}
+		if out != "" {
+			rows = append(rows, out)
+		}
+	}
+	return strings.Join(rows, "\n"), nil
+}
+
+func renderRow(row model.GridRow, cells []string, totalWidth int, cache *RendererCache) (string, error) {
+	widths := row.Widths(totalWidth)
 	if len(widths) == 0 {
 		return "", nil
 	}
 
-	rendered := make([]string, len(columns))
+	rendered := make([]string, len(row.Cells))
 	maxLines := 0
 
-	for i, col := range columns {
+	for i, cell := range row.Cells {
 		if i >= len(widths) {
 			break
 		}
 
 		w := widths[i]
-		out, err := renderColumn(col, w, cache)
-		if err != nil {
-			rendered[i] = col
-			continue
+		var out string
+		switch {
+		case cell.Grid != nil:
+			nested, err := RenderGrid(*cell.Grid, cells, w, cache)
+			if err != nil {
+				return "", err
+			}
+			out = nested
+		case cell.Leaf >= 0 && cell.Leaf < len(cells):
+			var err error
+			out, err = renderColumn(cells[cell.Leaf], w, cache)
+			if err != nil {
+				out = cells[cell.Leaf]
+			}
 		}
 		rendered[i] = out
 
@@ -42,6 +78,11 @@ func RenderColumns(columns []string, layout model.ColumnLayout, totalWidth int,
 		if i >= len(widths) {
 			break
 		}
+		if i > 0 {
+			paddedCols = append(paddedCols, padToHeight("", columnGap, maxLines))
+		}
+		cell := row.Cells[i]
+		col = Place(col, widths[i], maxLines, cell.Align, cell.VAlign)
 		paddedCols = append(paddedCols, padToHeight(col, widths[i], maxLines))
 	}
 
@@ -49,6 +90,10 @@ func RenderColumns(columns []string, layout model.ColumnLayout, totalWidth int,
 }
 
 func renderColumn(content string, width int, cache *RendererCache) (string, error) {
+	if strings.TrimSpace(content) == "" {
+		return "", nil
+	}
+
 	r, err := cache.Get(width)
 	if err != nil {
 		return content, err
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"charm.land/lipgloss/v2"
 	"github.com/jedwards1230/deck/internal/model"
 )
 
@@ -131,3 +132,46 @@ func TestRenderColumns(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderGrid(t *testing.T) {
+	nested := &model.Grid{Rows: []model.GridRow{
+		{Ratios: []int{1}, Cells: []model.GridCell{{Leaf: 2}}},
+		{Ratios: []int{1}, Cells: []model.GridCell{{Leaf: 3}}},
+	}}
+	grid := model.Grid{Rows: []model.GridRow{
+		{Ratios: []int{1}, Cells: []model.GridCell{{Leaf: 0}}},
+		{Ratios: []int{2, 1}, Cells: []model.GridCell{{Leaf: 1}, {Grid: nested}}},
+		{Ratios: []int{1}, Cells: []model.GridCell{{Leaf: 4}}},
+	}}
+	cells := []string{"HEADER", "MAIN\n\nline two\n\nline three", "TOPNOTE", "BOTTOMNOTE", "FOOTROW"}
+
+	got, err := RenderGrid(grid, cells, 80, NewRendererCache(true))
+	if err != nil {
+		t.Fatalf("RenderGrid() error: %v", err)
+	}
+
+	lines := strings.Split(got, "\n")
+	find := func(s string) int {
+		for i, line := range lines {
+			if strings.Contains(line, s) {
+				return i
+			}
+		}
+		t.Fatalf("RenderGrid() missing %q, got:\n%s", s, got)
+		return -1
+	}
+	header, main, top, bottom, foot := find("HEADER"), find("MAIN"), find("TOPNOTE"), find("BOTTOMNOTE"), find("FOOTROW")
+
+	if !(header < main && main == top && top < bottom && bottom < foot) {
+		t.Errorf("row order wrong: header %d, main %d, top note %d, bottom note %d, foot %d\n%s",
+			header, main, top, bottom, foot, got)
+	}
+	if strings.Index(lines[main], "MAIN") > strings.Index(lines[top], "TOPNOTE") {
+		t.Errorf("nested grid should sit right of the main cell:\n%s", got)
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > 80 {
+			t.Errorf("line %d is %d wide, want at most 80", i, w)
+		}
+	}
+}
@@ -16,11 +16,18 @@ var (
 				Foreground(lipgloss.Color("238"))
 )
 
+// Section is the section of the current slide, for the footer template's
+// {section}, {section_slide} and {section_total}.
+type Section struct {
+	Name         string
+	Slide, Total int // 0-based page within the section, and its page count
+}
+
 // RenderFooter creates the footer bar with author/date on the left and
 // slide paging on the right, spanning the given width.
-func RenderFooter(fm model.Frontmatter, currentSlide, totalSlides, width int) string {
+func RenderFooter(fm model.Frontmatter, currentSlide, totalSlides int, section Section, width int) string {
 	left := buildLeftFooter(fm)
-	right := buildRightFooter(fm, currentSlide, totalSlides)
+	right := buildRightFooter(fm, currentSlide, totalSlides, section)
 
 	leftWidth := lipgloss.Width(left)
 	rightWidth := lipgloss.Width(right)
@@ -29,10 +36,15 @@ func RenderFooter(fm model.Frontmatter, currentSlide, totalSlides, width int) st
 		gap = 0
 	}
 
-	divider := footerDividerStyle.Render(strings.Repeat("\u2500", width))
 	content := left + strings.Repeat(" ", gap) + right
 
-	return divider + "\n" + content
+	return Divider(width) + "\n" + content
+}
+
+// Divider returns a horizontal rule spanning the given width, as drawn
+// above the footer.
+func Divider(width int) string {
+	return footerDividerStyle.Render(strings.Repeat("\u2500", width))
 }
 
 func buildLeftFooter(fm model.Frontmatter) string {
@@ -49,10 +61,10 @@ func buildLeftFooter(fm model.Frontmatter) string {
 	return footerStyle.Render(strings.Join(parts, " \u00b7 "))
 }
 
-func buildRightFooter(fm model.Frontmatter, currentSlide, totalSlides int) string {
+func buildRightFooter(fm model.Frontmatter, currentSlide, totalSlides int, section Section) string {
 	// Custom footer template takes precedence.
 	if fm.Footer != "" {
-		return footerStyle.Render(expandFooterTemplate(fm.Footer, fm, currentSlide, totalSlides))
+		return footerStyle.Render(expandFooterTemplate(fm.Footer, fm, currentSlide, totalSlides, section))
 	}
 
 	if fm.Paging != "" {
@@ -65,12 +77,23 @@ func buildRightFooter(fm model.Frontmatter, currentSlide, totalSlides int) strin
 	return ""
 }
 
-func expandFooterTemplate(tmpl string, fm model.Frontmatter, currentSlide, totalSlides int) string {
-	r := strings.NewReplacer(
+func expandFooterTemplate(tmpl string, fm model.Frontmatter, currentSlide, totalSlides int, section Section) string {
+	pairs := []string{
 		"{author}", fm.Author,
 		"{date}", fm.Date,
 		"{current_slide}", fmt.Sprintf("%d", currentSlide+1),
 		"{total_slides}", fmt.Sprintf("%d", totalSlides),
-	)
-	return r.Replace(tmpl)
+		"{section}", section.Name,
+		"{section_slide}", fmt.Sprintf("%d", section.Slide+1),
+		"{section_total}", fmt.Sprintf("%d", section.Total),
+	}
+	// Custom frontmatter keys come after the built-in names, which win.
+	for key, value := range fm.Extra {
+		switch value.(type) {
+		case map[string]any, []any, nil:
+		default:
+			pairs = append(pairs, "{"+key+"}", fmt.Sprint(value))
+		}
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
 }
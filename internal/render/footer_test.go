@@ -15,7 +15,7 @@ func TestRenderFooter(t *testing.T) {
 			Paging: "Slide %d / %d",
 		}
 
-		got := RenderFooter(fm, 0, 5, 80)
+		got := RenderFooter(fm, 0, 5, Section{}, 80)
 
 		if !strings.Contains(got, "Alice") {
 			t.Errorf("RenderFooter() missing author, got %q", got)
@@ -28,7 +28,7 @@ func TestRenderFooter(t *testing.T) {
 	t.Run("with empty frontmatter", func(t *testing.T) {
 		fm := model.Frontmatter{}
 
-		got := RenderFooter(fm, 0, 1, 80)
+		got := RenderFooter(fm, 0, 1, Section{}, 80)
 
 		// Should still render without panicking; divider line is always present
 		if !strings.Contains(got, "\u2500") {
@@ -43,7 +43,7 @@ func TestRenderFooter(t *testing.T) {
 			Footer: "{author} - {current_slide}/{total_slides}",
 		}
 
-		got := RenderFooter(fm, 2, 10, 80)
+		got := RenderFooter(fm, 2, 10, Section{}, 80)
 
 		if !strings.Contains(got, "Bob") {
 			t.Errorf("RenderFooter() missing author in custom footer, got %q", got)
@@ -61,6 +61,7 @@ func TestExpandFooterTemplate(t *testing.T) {
 		fm           model.Frontmatter
 		currentSlide int
 		totalSlides  int
+		section      Section
 		wantContains []string
 	}{
 		{
@@ -79,6 +80,24 @@ func TestExpandFooterTemplate(t *testing.T) {
 			totalSlides:  5,
 			wantContains: []string{"Slide 1"},
 		},
+		{
+			name:         "section variables",
+			tmpl:         "{section} · {section_slide}/{section_total}",
+			fm:           model.Frontmatter{},
+			currentSlide: 46,
+			totalSlides:  112,
+			section:      Section{Name: "Architecture", Slide: 2, Total: 9},
+			wantContains: []string{"Architecture · 3/9"},
+		},
+		{
+			name:         "custom frontmatter keys",
+			tmpl:         "{event} {year} {section}",
+			fm:           model.Frontmatter{Extra: map[string]any{"event": "KubeCon", "year": 2026, "section": "ignored"}},
+			currentSlide: 0,
+			totalSlides:  1,
+			section:      Section{Name: "Intro"},
+			wantContains: []string{"KubeCon 2026 Intro"},
+		},
 		{
 			name:         "no variables",
 			tmpl:         "static footer",
@@ -91,7 +110,7 @@ func TestExpandFooterTemplate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := expandFooterTemplate(tt.tmpl, tt.fm, tt.currentSlide, tt.totalSlides)
+			got := expandFooterTemplate(tt.tmpl, tt.fm, tt.currentSlide, tt.totalSlides, tt.section)
 			for _, want := range tt.wantContains {
 				if !strings.Contains(got, want) {
 					t.Errorf("expandFooterTemplate() = %q, want it to contain %q", got, want)
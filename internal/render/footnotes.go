@@ -0,0 +1,32 @@
+package render
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// footnoteMargin lines footnotes up with the text glamour renders.
+const footnoteMargin = 2
+
+// RenderFootnotes renders footnotes as a dimmed block, one per line in
+// number order with long text wrapped under itself, for the bottom of the
+// slide. It returns "" when there are none.
+func RenderFootnotes(footnotes []model.Footnote, width int) string {
+	var lines []string
+	for _, f := range footnotes {
+		marker := f.Marker() + " "
+		indent := strings.Repeat(" ", footnoteMargin+lipgloss.Width(marker))
+		wrapped := ansi.Wrap(f.Text, max(10, width-lipgloss.Width(indent)-1), "")
+		for i, line := range strings.Split(wrapped, "\n") {
+			prefix := indent
+			if i == 0 {
+				prefix = strings.Repeat(" ", footnoteMargin) + marker
+			}
+			lines = append(lines, footerStyle.Render(prefix+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
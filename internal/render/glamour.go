@@ -10,12 +10,19 @@ import (
 // glamourGutter is the internal padding glamour adds to rendered content.
 const glamourGutter = 3
 
-// RendererCache caches a glamour TermRenderer keyed by width, avoiding
+// maxCachedWidths bounds the renderers a RendererCache keeps, so repeated
+// terminal resizes do not grow it without limit.
+const maxCachedWidths = 16
+
+// RendererCache caches glamour TermRenderers keyed by width, avoiding
 // re-creation on every render when the terminal size has not changed.
+// Layouts render cells at several widths, so up to maxCachedWidths widths
+// are kept; past that the cache starts over.
 type RendererCache struct {
 	mu       sync.Mutex
-	renderer *glamour.TermRenderer
+	renderer *glamour.TermRenderer // most recently used
 	width    int
+	byWidth  map[int]*glamour.TermRenderer
 	isDark   bool
 }
 
@@ -38,6 +45,10 @@ func (c *RendererCache) Get(width int) (*glamour.TermRenderer, error) {
 	if c.renderer != nil && c.width == renderWidth {
 		return c.renderer, nil
 	}
+	if r, ok := c.byWidth[renderWidth]; ok {
+		c.renderer, c.width = r, renderWidth
+		return r, nil
+	}
 
 	style := styles.DarkStyleConfig
 	if !c.isDark {
@@ -52,6 +63,10 @@ func (c *RendererCache) Get(width int) (*glamour.TermRenderer, error) {
 		return nil, err
 	}
 
+	if c.byWidth == nil || len(c.byWidth) >= maxCachedWidths {
+		c.byWidth = make(map[int]*glamour.TermRenderer)
+	}
+	c.byWidth[renderWidth] = r
 	c.renderer = r
 	c.width = renderWidth
 	return r, nil
@@ -63,4 +78,5 @@ func (c *RendererCache) Invalidate() {
 	defer c.mu.Unlock()
 	c.renderer = nil
 	c.width = 0
+	c.byWidth = nil
 }
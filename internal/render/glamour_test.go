@@ -91,6 +91,18 @@ func TestRendererCacheGet(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps a bounded number of widths", func(t *testing.T) {
+		cache := NewRendererCache(true)
+		for width := 40; width < 40+3*maxCachedWidths; width++ {
+			if _, err := cache.Get(width); err != nil {
+				t.Fatalf("Get(%d) error: %v", width, err)
+			}
+		}
+		if n := len(cache.byWidth); n > maxCachedWidths {
+			t.Errorf("cache holds %d renderers, want at most %d", n, maxCachedWidths)
+		}
+	})
+
 	t.Run("light mode returns a renderer", func(t *testing.T) {
 		cache := NewRendererCache(false)
 		r, err := cache.Get(80)
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jedwards1230/deck/internal/model"
+)
+
+// Place positions rendered content as one block within a width×height
+// area: align is left, center or right and valign top, middle or bottom,
+// with empty values meaning left and top. The blank margins and padding
+// glamour adds around content are ignored when measuring it, so the
+// visible text itself is placed. Left and top alignment leave the content
+// as rendered.
+func Place(content string, width, height int, align, valign string) string {
+	horizontal := align == model.AlignCenter || align == model.AlignRight
+	vertical := valign == model.VAlignMiddle || valign == model.VAlignBottom
+	if !horizontal && !vertical {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	visible := func(line string) int {
+		return ansi.StringWidth(strings.TrimRight(ansi.Strip(line), " "))
+	}
+
+	// Keep only the lines from the first to the last with visible text.
+	first, last := -1, -1
+	for i, line := range lines {
+		if visible(line) > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return content
+	}
+	lines = lines[first : last+1]
+
+	if horizontal {
+		// Measure the block of visible text.
+		indent, blockWidth := -1, 0
+		for _, line := range lines {
+			plain := strings.TrimRight(ansi.Strip(line), " ")
+			if plain == "" {
+				continue
+			}
+			lead := len(plain) - len(strings.TrimLeft(plain, " "))
+			if indent < 0 || lead < indent {
+				indent = lead
+			}
+			blockWidth = max(blockWidth, ansi.StringWidth(plain))
+		}
+		blockWidth -= indent
+
+		// A right-aligned block keeps glamour's left margin on the right.
+		offset := (width - blockWidth) / 2
+		if align == model.AlignRight {
+			offset = width - blockWidth - indent
+		}
+		pad := strings.Repeat(" ", max(offset, 0))
+
+		for i, line := range lines {
+			w := visible(line)
+			if w == 0 {
+				lines[i] = ""
+				continue
+			}
+			line = ansi.Truncate(line, w, "")
+			line = ansi.TruncateLeft(line, min(indent, w), "")
+			lines[i] = pad + line
+		}
+	}
+
+	top := 0
+	switch valign {
+	case model.VAlignMiddle:
+		top = max((height-len(lines))/2, 0)
+	case model.VAlignBottom:
+		top = max(height-len(lines), 0)
+	}
+	return strings.Repeat("\n", top) + strings.Join(lines, "\n")
+}
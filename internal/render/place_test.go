@@ -0,0 +1,38 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlace(t *testing.T) {
+	// Glamour-like output with blank margin lines around the text.
+	content := "  \n  one        \n  three      \n  "
+
+	tests := []struct {
+		name          string
+		align, valign string
+		want          []string
+	}{
+		{"left top unchanged", "", "", strings.Split(content, "\n")},
+		{"right", "right", "", []string{"           one", "           three"}},
+		{"center", "center", "", []string{"      one", "      three"}},
+		{"bottom", "", "bottom", []string{"", "", "", "  one        ", "  three      "}},
+		{"middle", "left", "middle", []string{"", "  one        ", "  three      "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Split(Place(content, 18, 5, tt.align, tt.valign), "\n")
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Place() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceBlank(t *testing.T) {
+	if got := Place("\n   \n", 20, 5, "center", "middle"); got != "\n   \n" {
+		t.Errorf("Place() of blank content = %q, want it unchanged", got)
+	}
+}
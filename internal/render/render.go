@@ -3,20 +3,37 @@ package render
 import (
 	"strings"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/jedwards1230/deck/internal/model"
 )
 
 // RenderSlide renders the visible portion of a slide at the given width.
-// If the slide has a column layout with column content, it renders in
-// multi-column mode. Otherwise renders as a single block of markdown.
+// If the slide has a grid layout, it renders the cells revealed so far.
+// Otherwise it renders as a single block of markdown.
 func RenderSlide(slide model.Slide, chunkIndex, width int, cache *RendererCache) (string, error) {
-	// Column layout rendering
-	if slide.Layout != nil && len(slide.Columns) > 0 {
-		return RenderColumns(slide.Columns, *slide.Layout, width, cache)
+	// Grid layout rendering, revealing cell content chunk by chunk
+	if slide.Grid != nil {
+		return RenderGrid(*slide.Grid, slide.VisibleColumns(chunkIndex), width, cache)
 	}
 
 	// Standard single-column rendering
-	content := strings.TrimSpace(slide.VisibleContent(chunkIndex))
+	return renderMarkdown(strings.TrimSpace(slide.VisibleContent(chunkIndex)), width, cache)
+}
+
+// RenderNotes renders a slide's speaker notes as markdown at the given
+// width, separating notes with a blank line and dropping the blank lines
+// glamour adds around them. It returns "" when the slide has no notes.
+func RenderNotes(notes []string, width int, cache *RendererCache) (string, error) {
+	rendered, err := renderMarkdown(strings.TrimSpace(strings.Join(notes, "\n\n")), width, cache)
+	if err != nil {
+		return rendered, err
+	}
+	return trimBlankLines(rendered), nil
+}
+
+// renderMarkdown renders content with the renderer for width. It returns
+// "" for empty content, and content itself when rendering fails.
+func renderMarkdown(content string, width int, cache *RendererCache) (string, error) {
 	if content == "" {
 		return "", nil
 	}
@@ -30,6 +47,19 @@ func RenderSlide(slide model.Slide, chunkIndex, width int, cache *RendererCache)
 	if err != nil {
 		return content, err
 	}
-
 	return rendered, nil
 }
+
+// trimBlankLines drops the leading and trailing lines of s that are blank
+// once styling is stripped.
+func trimBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	blank := func(line string) bool { return strings.TrimSpace(ansi.Strip(line)) == "" }
+	for len(lines) > 0 && blank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && blank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
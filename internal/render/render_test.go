@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/jedwards1230/deck/internal/model"
 )
 
@@ -111,3 +112,42 @@ func TestRenderSlide(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderNotes(t *testing.T) {
+	cache := NewRendererCache(true)
+
+	got, err := RenderNotes([]string{"- **first** point", "Second note"}, 60, cache)
+	if err != nil {
+		t.Fatalf("RenderNotes() error: %v", err)
+	}
+	plain := ansi.Strip(got)
+	if strings.Contains(plain, "**") || strings.Contains(plain, "- first") {
+		t.Errorf("RenderNotes() did not render markdown, got:\n%s", plain)
+	}
+	if !strings.Contains(plain, "first point") || !strings.Contains(plain, "Second note") {
+		t.Errorf("RenderNotes() missing note text, got:\n%s", plain)
+	}
+
+	if got, _ := RenderNotes(nil, 60, cache); got != "" {
+		t.Errorf("RenderNotes(nil) = %q, want empty", got)
+	}
+}
+
+func TestRenderFootnotes(t *testing.T) {
+	footnotes := []model.Footnote{
+		{Number: 1, Text: "Smith et al., 2021."},
+		{Number: 2, Text: "Jones, a much longer citation that wraps onto another line."},
+	}
+
+	got := ansi.Strip(RenderFootnotes(footnotes, 40))
+	want := "  ¹ Smith et al., 2021.\n" +
+		"  ² Jones, a much longer citation that\n" +
+		"    wraps onto another line."
+	if got != want {
+		t.Errorf("RenderFootnotes() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := RenderFootnotes(nil, 40); got != "" {
+		t.Errorf("RenderFootnotes(nil) = %q, want empty", got)
+	}
+}
@@ -42,7 +42,7 @@ func Search(slides []model.Slide, query string, currentSlide int) Result {
 	// Search forward from current slide (wrapping)
 	for i := range len(slides) {
 		idx := (currentSlide + i) % len(slides)
-		content := slides[idx].VisibleContent(len(slides[idx].Chunks) - 1)
+		content := searchText(slides[idx])
 		if pattern.MatchString(content) {
 			return Result{SlideIndex: idx, Found: true}
 		}
@@ -80,7 +80,7 @@ func SearchPrev(slides []model.Slide, query string, currentSlide int) Result {
 	// Search backward from current slide (wrapping)
 	for i := range len(slides) {
 		idx := (currentSlide - 1 - i + len(slides)) % len(slides)
-		content := slides[idx].VisibleContent(len(slides[idx].Chunks) - 1)
+		content := searchText(slides[idx])
 		if pattern.MatchString(content) {
 			return Result{SlideIndex: idx, Found: true}
 		}
@@ -89,6 +89,25 @@ func SearchPrev(slides []model.Slide, query string, currentSlide int) Result {
 	return Result{Found: false}
 }
 
+// FindID returns the index of the slide whose slide comment sets id or,
+// failing that, the first slide with a heading whose anchor is id.
+func FindID(slides []model.Slide, id string) Result {
+	if i := model.AnchorIndex(slides, id); i >= 0 {
+		return Result{SlideIndex: i, Found: true}
+	}
+	return Result{Found: false}
+}
+
+// searchText is the text of a slide that queries match against: its
+// explicit title, if any, and all of its content.
+func searchText(slide model.Slide) string {
+	content := slide.VisibleContent(len(slide.Chunks) - 1)
+	if slide.Meta.Title != "" {
+		return slide.Meta.Title + "\n" + content
+	}
+	return content
+}
+
 func searchLiteral(slides []model.Slide, query string, currentSlide int, caseInsensitive bool) Result {
 	if caseInsensitive {
 		query = strings.ToLower(query)
@@ -96,7 +115,7 @@ func searchLiteral(slides []model.Slide, query string, currentSlide int, caseIns
 
 	for i := range len(slides) {
 		idx := (currentSlide + i) % len(slides)
-		content := slides[idx].VisibleContent(len(slides[idx].Chunks) - 1)
+		content := searchText(slides[idx])
 		if caseInsensitive {
 			content = strings.ToLower(content)
 		}
@@ -156,3 +156,36 @@ func TestSearchPrev(t *testing.T) {
 		}
 	})
 }
+
+func TestSearchMatchesExplicitTitle(t *testing.T) {
+	slides := makeSlides("# One", "![diagram](arch.png)")
+	slides[1].Meta.Title = "Architecture"
+
+	got := Search(slides, "Architecture", 0)
+	if !got.Found || got.SlideIndex != 1 {
+		t.Errorf("Search() = %+v, want slide 1", got)
+	}
+}
+
+func TestFindID(t *testing.T) {
+	slides := makeSlides("# One", "# Two", "# Three")
+	slides[2].Meta.ID = "outro"
+
+	if got := FindID(slides, "outro"); !got.Found || got.SlideIndex != 2 {
+		t.Errorf("FindID(outro) = %+v, want slide 2", got)
+	}
+	if got := FindID(slides, "missing"); got.Found {
+		t.Errorf("FindID(missing) = %+v, want not found", got)
+	}
+	if got := FindID(slides, ""); got.Found {
+		t.Errorf("FindID(\"\") = %+v, want not found", got)
+	}
+
+	slides[1].Anchors = []string{"outro", "two"}
+	if got := FindID(slides, "two"); !got.Found || got.SlideIndex != 1 {
+		t.Errorf("FindID(two) = %+v, want the slide with that heading anchor", got)
+	}
+	if got := FindID(slides, "outro"); got.SlideIndex != 2 {
+		t.Errorf("FindID(outro) = %+v, want the slide id to win over a heading", got)
+	}
+}
@@ -3,14 +3,25 @@ package watch
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/fsnotify/fsnotify"
 
 	"github.com/jedwards1230/deck/internal/app"
+	"github.com/jedwards1230/deck/internal/parse"
 )
 
-// Watch monitors a file for changes and sends FileChangedMsg to the program.
+// Watch monitors a deck, and every file it includes or excerpts, for
+// changes and sends FileChangedMsg with the deck's content to the program.
+// For a directory deck, adding, removing or renaming a .md file in the
+// directory also triggers a reload.
+//
+// Directories are watched rather than files so editors that save by
+// replacing the file are still noticed, and so files added to an include
+// list after startup are picked up on the next reload. A named file that
+// does not exist yet is watched through its directory, so creating it
+// reloads the deck.
 func Watch(filePath string, p *tea.Program) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -19,7 +30,21 @@ func Watch(filePath string, p *tea.Program) {
 	}
 	defer func() { _ = watcher.Close() }()
 
-	if err := watcher.Add(filePath); err != nil {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+		return
+	}
+	root := absPath(filePath)
+	isDir := info.IsDir()
+
+	content, err := parse.ReadDeck(filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+		return
+	}
+	sources, err := track(watcher, filePath, content, isDir)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
 		return
 	}
@@ -30,13 +55,21 @@ func Watch(filePath string, p *tea.Program) {
 			if !ok {
 				return
 			}
-			if event.Has(fsnotify.Write) {
-				data, err := os.ReadFile(filePath)
-				if err != nil {
-					continue
-				}
-				p.Send(app.FileChangedMsg{Content: string(data)})
+			name := absPath(event.Name)
+			changed := sources[name] && event.Has(fsnotify.Write|fsnotify.Create)
+			membership := isDir && filepath.Dir(name) == root && parse.IsDeckFile(name) &&
+				event.Has(fsnotify.Create|fsnotify.Remove|fsnotify.Rename)
+			if !changed && !membership {
+				continue
+			}
+			content, err := parse.ReadDeck(filePath)
+			if err != nil {
+				continue
 			}
+			if s, err := track(watcher, filePath, content, isDir); err == nil {
+				sources = s
+			}
+			p.Send(app.FileChangedMsg{Content: content})
 		case _, ok := <-watcher.Errors:
 			if !ok {
 				return
@@ -44,3 +77,51 @@ func Watch(filePath string, p *tea.Program) {
 		}
 	}
 }
+
+// track adds the directories of the deck and the files it includes or
+// excerpts to the watcher and returns the set of their absolute paths,
+// including the files it names that are missing.
+func track(watcher *fsnotify.Watcher, filePath, content string, isDir bool) (map[string]bool, error) {
+	pres := parse.Parse(content, parse.Options{Path: filePath})
+	root := absPath(filePath)
+	sources := map[string]bool{root: true}
+	for _, src := range pres.Sources {
+		sources[absPath(src)] = true
+	}
+
+	watched := make(map[string]bool)
+	for _, dir := range watcher.WatchList() {
+		watched[dir] = true
+	}
+	for src := range sources {
+		dir := filepath.Dir(src)
+		if src == root && isDir {
+			dir = root
+		}
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return nil, err
+		}
+		watched[dir] = true
+	}
+
+	// A missing file's directory may not exist either, in which case the
+	// file is only noticed on the next reload.
+	for _, path := range pres.Missing {
+		missing := absPath(path)
+		sources[missing] = true
+		if dir := filepath.Dir(missing); !watched[dir] && watcher.Add(dir) == nil {
+			watched[dir] = true
+		}
+	}
+	return sources, nil
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
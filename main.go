@@ -2,13 +2,19 @@ package main
 
 import (
 	_ "embed"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
 
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/jedwards1230/deck/internal/app"
+	"github.com/jedwards1230/deck/internal/convert"
+	"github.com/jedwards1230/deck/internal/lint"
+	"github.com/jedwards1230/deck/internal/parse"
 	"github.com/jedwards1230/deck/internal/version"
 	"github.com/jedwards1230/deck/internal/watch"
 )
@@ -19,11 +25,24 @@ var tutorial string
 const usage = `deck — terminal slide presenter
 
 Usage:
-  deck [file]           Present a markdown file
+  deck [flags] [file]   Present a markdown file
+  deck [flags] [dir]    Present every .md file in a directory, in order
   cat file | deck       Read slides from stdin
   deck                  Show built-in tutorial
+  deck lint [file...]   Check decks for problems (see deck lint -h)
+  deck convert [file]   Convert a slides, presenterm, Marp or reveal.js
+                        deck (see deck convert -h)
 
 Flags:
+  --var key=value       Set a deck variable, overriding frontmatter vars
+                        (repeatable)
+  --profile name        Present for an audience profile, dropping slides
+                        and only regions meant for others
+  --lang code           Present lang regions in this language
+  --notes-lang code     Show speaker notes in this language instead
+  --show-hidden         Present hidden slides too
+  --strict              Refuse to present when the frontmatter has errors
+                        or keys deck does not know
   -h, --help            Show this help
   -v, --version         Show version
 
@@ -32,13 +51,67 @@ Keys (in-app):
   j / k                 Fwd / Back  gg / G    First / Last
   3G                    Go to 3     /         Search
   ctrl+n / N            Next / prev match
+  ]] / [[               Next / prev section
+  f                     Follow link b         Back from jump
   ctrl+e                Execute code block
+  s                     Notes       L         Next language
   y                     Copy code   q         Quit
 
 See README.md for slide format, frontmatter, layouts, and reveal syntax.
 `
 
+const lintUsage = `Usage: deck lint [--format text|json] [--var key=value] [--profile name]
+                 [--lang code] [--strict] file...
+
+Reports problems in each deck (a file or directory) with file:line
+positions. Exits 1 when any error or warning is found, 2 on usage or read
+errors.
+
+Flags:
+  --format string       Output format: text or json (default "text")
+  --var key=value       Set a deck variable, overriding frontmatter vars
+                        (repeatable)
+  --profile name        Check the deck as presented for an audience profile
+  --lang code           Check the deck as presented in this language
+  --strict              Report frontmatter keys deck does not know as
+                        errors
+`
+
+const convertUsage = `Usage: deck convert [--from format] [-o file] file
+
+Converts a deck written for another markdown slide tool to deck's format,
+writing it to stdout or the -o file. What cannot be translated exactly is
+reported on stderr with file:line positions. Exits 1 when the deck cannot
+be converted, 2 on usage or read errors.
+
+Flags:
+  --from string         Source format: slides, presenterm, marp or reveal
+                        (default: detected from the file)
+  -o string             Write the converted deck to this file
+`
+
+// varFlags collects repeated --var key=value flags.
+type varFlags map[string]string
+
+func (v varFlags) String() string { return "" }
+
+func (v varFlags) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("want key=value, got %q", s)
+	}
+	v[key] = value
+	return nil
+}
+
 func main() {
+	if len(os.Args) >= 2 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "convert" {
+		os.Exit(runConvert(os.Args[2:]))
+	}
+
 	if len(os.Args) == 2 {
 		switch os.Args[1] {
 		case "--version", "-v", "version":
@@ -50,13 +123,52 @@ func main() {
 		}
 	}
 
-	content, filePath, err := loadContent()
+	fs := flag.NewFlagSet("deck", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	vars := varFlags{}
+	fs.Var(vars, "var", "set a deck variable (key=value)")
+	profile := fs.String("profile", "", "present for an audience profile")
+	lang := fs.String("lang", "", "present lang regions in this language")
+	notesLang := fs.String("notes-lang", "", "show speaker notes in this language")
+	showHidden := fs.Bool("show-hidden", false, "present hidden slides")
+	strict := fs.Bool("strict", false, "reject frontmatter errors and unknown keys")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
+	}
+
+	content, filePath, err := loadContent(fs.Args())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	m := app.New(content, filePath)
+	m := app.NewWithOptions(content, app.Options{
+		Parse: parse.Options{
+			Path:      filePath,
+			Vars:      vars,
+			Profile:   *profile,
+			Lang:      *lang,
+			NotesLang: *notesLang,
+			Strict:    *strict,
+		},
+		ShowHidden: *showHidden,
+	})
+
+	// Frontmatter problems are shown in the presenter too, but the terminal
+	// keeps these after it exits.
+	if problems := m.FrontmatterProblems(); len(problems) > 0 {
+		name := filePath
+		if name == "" {
+			name = "<stdin>"
+		}
+		_ = lint.WriteText(os.Stderr, []lint.Result{{File: name, Diagnostics: problems}})
+		if *strict && lint.Failed([]lint.Result{{Diagnostics: problems}}) {
+			os.Exit(1)
+		}
+	}
 
 	p := tea.NewProgram(m)
 
@@ -71,7 +183,7 @@ func main() {
 	}
 }
 
-func loadContent() (content string, filePath string, err error) {
+func loadContent(args []string) (content string, filePath string, err error) {
 	// Check for piped input
 	stat, _ := os.Stdin.Stat()
 	if (stat.Mode() & os.ModeCharDevice) == 0 {
@@ -83,15 +195,117 @@ func loadContent() (content string, filePath string, err error) {
 	}
 
 	// Check for file argument
-	if len(os.Args) < 2 {
+	if len(args) == 0 {
 		return tutorial, "", nil
 	}
 
-	path := os.Args[1]
-	data, err := os.ReadFile(path)
+	path := args[0]
+	data, err := parse.ReadDeck(path)
 	if err != nil {
 		return "", "", fmt.Errorf("reading %s: %w", path, err)
 	}
 
-	return string(data), path, nil
+	return data, path, nil
+}
+
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, lintUsage) }
+	format := fs.String("format", "text", "output format: text or json")
+	vars := varFlags{}
+	fs.Var(vars, "var", "set a deck variable (key=value)")
+	profile := fs.String("profile", "", "check the deck for an audience profile")
+	lang := fs.String("lang", "", "check the deck in this language")
+	strict := fs.Bool("strict", false, "report unknown frontmatter keys as errors")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	results := make([]lint.Result, 0, fs.NArg())
+	for _, path := range fs.Args() {
+		data, err := parse.ReadDeck(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: reading %s: %v\n", path, err)
+			return 2
+		}
+		results = append(results, lint.Result{File: path, Diagnostics: lint.Lint(data, parse.Options{Path: path, Vars: vars, Profile: *profile, Lang: *lang, Strict: *strict})})
+	}
+
+	write := lint.WriteText
+	if *format == "json" {
+		write = lint.WriteJSON
+	}
+	if err := write(os.Stdout, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if lint.Failed(results) {
+		return 1
+	}
+	return 0
+}
+
+func runConvert(args []string) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, convertUsage) }
+	from := fs.String("from", "", "source format: slides, presenterm, marp or reveal")
+	out := fs.String("o", "", "write the converted deck to this file")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *from != "" && !slices.Contains(convert.Formats, convert.Format(*from)) {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *from)
+		return 2
+	}
+
+	path := fs.Arg(0)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: reading %s: %v\n", path, err)
+		return 2
+	}
+	format := convert.Format(*from)
+	if format == "" {
+		format = convert.Detect(string(data))
+	}
+
+	deck, diags, err := convert.Convert(string(data), format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: converting %s: %v\n", path, err)
+		return 1
+	}
+	if err := lint.WriteText(os.Stderr, []lint.Result{{File: path, Diagnostics: diags}}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		_, err = io.WriteString(os.Stdout, deck)
+	} else {
+		err = os.WriteFile(*out, []byte(deck), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
 }